@@ -210,7 +210,7 @@ func (s *Client) retry(ctx context.Context, method string, endpoint string, head
 	exp.InitialInterval = 200 * time.Millisecond
 	exp.RandomizationFactor = 0.5
 	exp.MaxElapsedTime = 4 * time.Second
-	bs := backoff.WithMaxRetries(exp, 5)
+	bs := backoff.WithContext(backoff.WithMaxRetries(exp, 5), ctx)
 
 	err := backoff.RetryNotify(operation, bs, func(err error, d time.Duration) {
 
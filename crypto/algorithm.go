@@ -49,8 +49,43 @@ const (
 	AlgFalcon              // post-quantum signature (NIST alternate)
 	AlgMlKem768            // post-quantum KEM   (NIST FIPS 203)
 	AlgMlDsa65             // post-quantum signature (NIST FIPS 204)
+	AlgMlKem1024           // post-quantum KEM   (NIST FIPS 203, category 5)
+	AlgMlDsa44             // post-quantum signature (NIST FIPS 204, category 2)
+	AlgMlDsa87             // post-quantum signature (NIST FIPS 204, category 5)
+	AlgSlhDsaSha2_128s     // stateless hash-based signature (NIST FIPS 205, category 1)
+	AlgSlhDsaSha2_256s     // stateless hash-based signature (NIST FIPS 205, category 5)
 )
 
+// String returns the conventional name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case AlgEd25519:
+		return "Ed25519"
+	case AlgCurve25519:
+		return "Curve25519"
+	case AlgP256r1:
+		return "P-256"
+	case AlgFalcon:
+		return "Falcon"
+	case AlgMlKem768:
+		return "ML-KEM-768"
+	case AlgMlDsa65:
+		return "ML-DSA-65"
+	case AlgMlKem1024:
+		return "ML-KEM-1024"
+	case AlgMlDsa44:
+		return "ML-DSA-44"
+	case AlgMlDsa87:
+		return "ML-DSA-87"
+	case AlgSlhDsaSha2_128s:
+		return "SLH-DSA-SHA2-128s"
+	case AlgSlhDsaSha2_256s:
+		return "SLH-DSA-SHA2-256s"
+	default:
+		return "None"
+	}
+}
+
 func algToFoundation(a Algorithm) foundation.AlgId {
 	switch a {
 	case AlgEd25519:
@@ -65,6 +100,16 @@ func algToFoundation(a Algorithm) foundation.AlgId {
 		return foundation.AlgIdMlKem768
 	case AlgMlDsa65:
 		return foundation.AlgIdMlDsa65
+	case AlgMlKem1024:
+		return foundation.AlgIdMlKem1024
+	case AlgMlDsa44:
+		return foundation.AlgIdMlDsa44
+	case AlgMlDsa87:
+		return foundation.AlgIdMlDsa87
+	case AlgSlhDsaSha2_128s:
+		return foundation.AlgIdSlhDsaSha2_128s
+	case AlgSlhDsaSha2_256s:
+		return foundation.AlgIdSlhDsaSha2_256s
 	default:
 		return foundation.AlgIdNone
 	}
@@ -84,6 +129,16 @@ func algFromFoundation(id foundation.AlgId) Algorithm {
 		return AlgMlKem768
 	case foundation.AlgIdMlDsa65:
 		return AlgMlDsa65
+	case foundation.AlgIdMlKem1024:
+		return AlgMlKem1024
+	case foundation.AlgIdMlDsa44:
+		return AlgMlDsa44
+	case foundation.AlgIdMlDsa87:
+		return AlgMlDsa87
+	case foundation.AlgIdSlhDsaSha2_128s:
+		return AlgSlhDsaSha2_128s
+	case foundation.AlgIdSlhDsaSha2_256s:
+		return AlgSlhDsaSha2_256s
 	default:
 		return AlgNone
 	}
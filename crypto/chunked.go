@@ -0,0 +1,318 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// Chunked message layout:
+//
+//	magic(4) | version(1) | algorithm(1) | chunk length(4) | nonce prefix(7) | wrapped key length(4) | wrapped key | chunks
+//
+// The wrapped key is the random content key encrypted for the recipients as
+// a regular message. The chunks are sealed with the content key as in the
+// symmetric stream format, with the header up to the wrapped key length as
+// the associated data. All chunks but the last one have the same length, so
+// any chunk can be located and decrypted on its own.
+const (
+	chunkedVersion        = 1
+	chunkedFixedHeaderLen = 4 + 1 + 1 + 4 + symmetricStreamNoncePrefixLen
+	chunkedHeaderLen      = chunkedFixedHeaderLen + 4
+
+	// DefaultChunkLen is the plaintext length of a chunk of a chunked message
+	DefaultChunkLen = DefaultSymmetricChunkLen
+)
+
+var chunkedMagic = []byte("VCHK")
+
+// EncryptChunkedStream encrypts in for the recipients to the chunked message
+// format that can be decrypted with random access by NewChunkedDecrypter.
+func (c *Crypto) EncryptChunkedStream(in io.Reader, out io.Writer, recipients ...PublicKey) error {
+	w, err := c.NewChunkedEncryptWriter(out, recipients...)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(w, in); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// NewChunkedEncryptWriter returns a writer of a chunked message for the
+// recipients. Close must be called to write the last chunk; it does not close out.
+func (c *Crypto) NewChunkedEncryptWriter(out io.Writer, recipients ...PublicKey) (*SymmetricEncryptWriter, error) {
+	key, err := c.GenerateSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+
+	wrappedKey, err := c.Encrypt(key, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	return newChunkedEncryptWriter(out, SymmetricAES256GCM, key, wrappedKey, DefaultChunkLen)
+}
+
+func newChunkedEncryptWriter(
+	out io.Writer,
+	alg SymmetricAlgorithm,
+	key []byte,
+	wrappedKey []byte,
+	chunkLen int,
+) (*SymmetricEncryptWriter, error) {
+	header, s, err := newChunkedStream(alg, key, wrappedKey, chunkLen)
+	if err != nil {
+		return nil, err
+	}
+	return newSymmetricEncryptWriter(out, header, s, chunkLen), nil
+}
+
+// newChunkedStream returns the header of a new chunked message and the stream sealing its chunks
+func newChunkedStream(alg SymmetricAlgorithm, key, wrappedKey []byte, chunkLen int) ([]byte, *symmetricStream, error) {
+	header := make([]byte, chunkedHeaderLen+len(wrappedKey))
+	copy(header, chunkedMagic)
+	header[4] = chunkedVersion
+	header[5] = byte(alg)
+	binary.BigEndian.PutUint32(header[6:], uint32(chunkLen))
+	noncePrefix := header[10:chunkedFixedHeaderLen]
+	if _, err := io.ReadFull(rand.Reader, noncePrefix); err != nil {
+		return nil, nil, err
+	}
+	binary.BigEndian.PutUint32(header[chunkedFixedHeaderLen:], uint32(len(wrappedKey)))
+	copy(header[chunkedHeaderLen:], wrappedKey)
+
+	s, err := newSymmetricStream(alg, key, noncePrefix, header[:chunkedFixedHeaderLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, s, nil
+}
+
+// NewChunkedDecrypter opens the chunked message of size bytes read from r
+// with the private key of one of its recipients. Only the header is read.
+func (c *Crypto) NewChunkedDecrypter(r io.ReaderAt, size int64, key PrivateKey) (*ChunkedDecrypter, error) {
+	header, wrappedKey, err := readChunkedHeader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := c.Decrypt(wrappedKey, key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(contentKey)
+
+	return newChunkedDecrypter(r, size, header, int64(len(header)+len(wrappedKey)), contentKey)
+}
+
+func readChunkedHeader(r io.ReaderAt, size int64) (header, wrappedKey []byte, err error) {
+	header = make([]byte, chunkedHeaderLen)
+	if size < chunkedHeaderLen {
+		return nil, nil, ErrInvalidChunkedMessage
+	}
+	if _, err = r.ReadAt(header, 0); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(header[:4], chunkedMagic) {
+		return nil, nil, ErrInvalidChunkedMessage
+	}
+	if header[4] != chunkedVersion {
+		return nil, nil, ErrUnsupportedChunkedMessageVersion
+	}
+
+	wrappedKeyLen := int64(binary.BigEndian.Uint32(header[chunkedFixedHeaderLen:]))
+	if wrappedKeyLen > maxMessageInfoLen || chunkedHeaderLen+wrappedKeyLen > size {
+		return nil, nil, ErrInvalidChunkedMessage
+	}
+	wrappedKey = make([]byte, wrappedKeyLen)
+	if _, err = r.ReadAt(wrappedKey, chunkedHeaderLen); err != nil {
+		return nil, nil, err
+	}
+	return header, wrappedKey, nil
+}
+
+func newChunkedDecrypter(r io.ReaderAt, size int64, header []byte, dataOffset int64, key []byte) (*ChunkedDecrypter, error) {
+	chunkLen := int64(binary.BigEndian.Uint32(header[6:]))
+	if chunkLen == 0 || chunkLen > MaxSymmetricChunkLen {
+		return nil, ErrInvalidChunkedMessage
+	}
+	s, err := newSymmetricStream(SymmetricAlgorithm(header[5]), key, header[10:chunkedFixedHeaderLen], header[:chunkedFixedHeaderLen])
+	if err != nil {
+		return nil, err
+	}
+
+	sealedLen := chunkLen + SymmetricTagLen
+	dataLen := size - dataOffset
+	chunks := (dataLen + sealedLen - 1) / sealedLen
+	if chunks == 0 || dataLen-(chunks-1)*sealedLen < SymmetricTagLen || chunks-1 > int64(^uint32(0)) {
+		return nil, ErrInvalidChunkedMessage
+	}
+	return &ChunkedDecrypter{
+		r:          r,
+		s:          s,
+		dataOffset: dataOffset,
+		dataLen:    dataLen,
+		chunkLen:   chunkLen,
+		chunks:     chunks,
+		size:       dataLen - chunks*SymmetricTagLen,
+		cached:     -1,
+	}, nil
+}
+
+// ChunkedDecrypter decrypts a chunked message on demand. ReadAt decrypts only
+// the chunks covering the requested range and may be called concurrently;
+// Read and Seek keep an offset like io.SectionReader. A chunk that fails
+// authentication is reported with ErrSymmetricAuthentication.
+type ChunkedDecrypter struct {
+	r          io.ReaderAt
+	s          *symmetricStream
+	dataOffset int64
+	dataLen    int64
+	chunkLen   int64
+	chunks     int64
+	size       int64
+
+	mu     sync.Mutex
+	off    int64
+	cached int64
+	plain  []byte
+}
+
+var (
+	_ io.ReaderAt   = &ChunkedDecrypter{}
+	_ io.ReadSeeker = &ChunkedDecrypter{}
+)
+
+// Size returns the length of the decrypted data.
+func (d *ChunkedDecrypter) Size() int64 {
+	return d.size
+}
+
+func (d *ChunkedDecrypter) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrUnsupportedParameter
+	}
+	n := 0
+	for n < len(p) {
+		if off >= d.size {
+			return n, io.EOF
+		}
+		index := off / d.chunkLen
+		plain, err := d.chunk(index)
+		if err != nil {
+			return n, err
+		}
+		l := copy(p[n:], plain[off-index*d.chunkLen:])
+		n += l
+		off += int64(l)
+	}
+	return n, nil
+}
+
+func (d *ChunkedDecrypter) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	off := d.off
+	d.mu.Unlock()
+
+	n, err := d.ReadAt(p, off)
+
+	d.mu.Lock()
+	d.off = off + int64(n)
+	d.mu.Unlock()
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (d *ChunkedDecrypter) Seek(offset int64, whence int) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += d.off
+	case io.SeekEnd:
+		offset += d.size
+	default:
+		return 0, ErrUnsupportedParameter
+	}
+	if offset < 0 {
+		return 0, ErrUnsupportedParameter
+	}
+	d.off = offset
+	return offset, nil
+}
+
+// chunk returns the decrypted chunk, the last decrypted one is kept for sequential reads
+func (d *ChunkedDecrypter) chunk(index int64) ([]byte, error) {
+	d.mu.Lock()
+	if d.cached == index {
+		plain := d.plain
+		d.mu.Unlock()
+		return plain, nil
+	}
+	d.mu.Unlock()
+
+	start := index * (d.chunkLen + SymmetricTagLen)
+	sealedLen := d.chunkLen + SymmetricTagLen
+	if rest := d.dataLen - start; rest < sealedLen {
+		sealedLen = rest
+	}
+	sealed := make([]byte, sealedLen)
+	if n, err := d.r.ReadAt(sealed, d.dataOffset+start); n < len(sealed) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	plain, err := d.s.openAt(sealed[:0], sealed, uint32(index), index == d.chunks-1)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.cached, d.plain = index, plain
+	d.mu.Unlock()
+	return plain, nil
+}
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func chunkedTestMessage(t *testing.T, key, data []byte) []byte {
+	out := &bytes.Buffer{}
+	w, err := newChunkedEncryptWriter(out, SymmetricAES256GCM, key, []byte("wrapped key"), 16)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return out.Bytes()
+}
+
+func openChunkedTestMessage(key, msg []byte) (*ChunkedDecrypter, error) {
+	r := bytes.NewReader(msg)
+	header, wrappedKey, err := readChunkedHeader(r, r.Size())
+	if err != nil {
+		return nil, err
+	}
+	return newChunkedDecrypter(r, r.Size(), header, int64(len(header)+len(wrappedKey)), key)
+}
+
+func TestChunkedDecrypter(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, SymmetricKeyLen)
+	for _, size := range []int{0, 1, 16, 17, 100} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		d, err := openChunkedTestMessage(key, chunkedTestMessage(t, key, data))
+		require.NoError(t, err)
+		require.Equal(t, int64(size), d.Size())
+
+		all, err := io.ReadAll(d)
+		require.NoError(t, err)
+		require.Equal(t, data, all, "%d", size)
+
+		// every range, crossing chunk boundaries
+		for off := 0; off < size; off += 5 {
+			for l := 1; off+l <= size; l += 11 {
+				p := make([]byte, l)
+				n, err := d.ReadAt(p, int64(off))
+				require.NoError(t, err)
+				require.Equal(t, l, n)
+				require.Equal(t, data[off:off+l], p)
+			}
+		}
+
+		_, err = d.ReadAt(make([]byte, 1), int64(size))
+		require.Equal(t, io.EOF, err)
+		if size < 3 {
+			continue
+		}
+
+		p := make([]byte, 10)
+		n, err := d.ReadAt(p, int64(size)-3)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, 3, n)
+		require.Equal(t, data[size-3:], p[:3])
+
+		pos, err := d.Seek(-3, io.SeekEnd)
+		require.NoError(t, err)
+		require.Equal(t, int64(size-3), pos)
+		n, err = d.Read(p)
+		require.NoError(t, err)
+		require.Equal(t, data[size-3:], p[:n])
+	}
+}
+
+func TestChunkedDecrypter_Tampering(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, SymmetricKeyLen)
+	msg := chunkedTestMessage(t, key, make([]byte, 40))
+	dataOffset := chunkedHeaderLen + len("wrapped key")
+	sealed := 16 + SymmetricTagLen
+
+	// a modified chunk fails only the reads that cover it
+	modified := append([]byte{}, msg...)
+	modified[dataOffset+sealed] ^= 1
+	d, err := openChunkedTestMessage(key, modified)
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 16), 0)
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 1), 16)
+	require.ErrorIs(t, err, ErrSymmetricAuthentication)
+
+	// truncated at a chunk boundary, the new last chunk was not sealed as the last one
+	d, err = openChunkedTestMessage(key, msg[:dataOffset+2*sealed])
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 1), 16)
+	require.ErrorIs(t, err, ErrSymmetricAuthentication)
+
+	// the header is authenticated
+	modified = append([]byte{}, msg...)
+	modified[chunkedFixedHeaderLen-1] ^= 1
+	d, err = openChunkedTestMessage(key, modified)
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 1), 0)
+	require.ErrorIs(t, err, ErrSymmetricAuthentication)
+
+	_, err = openChunkedTestMessage(key, msg[:dataOffset+SymmetricTagLen-1])
+	require.ErrorIs(t, err, ErrInvalidChunkedMessage)
+	_, err = openChunkedTestMessage(key, msg[:chunkedHeaderLen-1])
+	require.ErrorIs(t, err, ErrInvalidChunkedMessage)
+	_, err = openChunkedTestMessage(key, []byte("not a chunked message at all"))
+	require.ErrorIs(t, err, ErrInvalidChunkedMessage)
+}
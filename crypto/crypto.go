@@ -37,6 +37,7 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/subtle"
+	"hash"
 	"io"
 
 	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
@@ -70,7 +71,7 @@ func (c *Crypto) generateKeypair(t KeyType, rnd foundation.Random) (PrivateKey,
 		t = DefaultKeyType
 	}
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(rnd)
 
@@ -119,7 +120,7 @@ func (c *Crypto) GenerateKeypairFromKeyMaterialForType(t KeyType, keyMaterial []
 	}
 	rnd := foundation.NewKeyMaterialRng()
 	rnd.ResetKeyMaterial(keyMaterial)
-	defer delete(rnd)
+	defer deleteObjects(rnd)
 
 	return c.GenerateKeypairForTypeWithCustomRng(rnd, t)
 }
@@ -140,7 +141,7 @@ func (c *Crypto) ImportPrivateKey(data []byte) (PrivateKey, error) {
 	data = unwrapKey(data)
 
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(random)
 	if err := kp.SetupDefaults(); err != nil {
@@ -181,7 +182,7 @@ func (c *Crypto) ImportPublicKey(data []byte) (PublicKey, error) {
 	data = unwrapKey(data)
 
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(random)
 	if err := kp.SetupDefaults(); err != nil {
@@ -207,7 +208,7 @@ func (c *Crypto) ImportPublicKey(data []byte) (PublicKey, error) {
 
 func (c *Crypto) ExportPrivateKey(key PrivateKey) ([]byte, error) {
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(random)
 	if err := kp.SetupDefaults(); err != nil {
@@ -222,7 +223,7 @@ func (c *Crypto) ExportPublicKey(key PublicKey) ([]byte, error) {
 
 func (c *Crypto) calculateFingerprint(key foundation.PublicKey) ([]byte, error) {
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(random)
 	if err := kp.SetupDefaults(); err != nil {
@@ -252,7 +253,7 @@ func (c *Crypto) EncryptWithPadding(data []byte, padding bool, recipients ...Pub
 	if err != nil {
 		return nil, err
 	}
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	buffer := bytes.NewBuffer(nil)
 	buffer.Grow(len(data))
@@ -269,7 +270,7 @@ func (c *Crypto) EncryptWithPadding(data []byte, padding bool, recipients ...Pub
 func (c *Crypto) Decrypt(data []byte, key PrivateKey) ([]byte, error) {
 
 	cipher := c.setupCipher(false)
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	if err := cipher.StartDecryptionWithKey(key.Identifier(), key.Unwrap(), nil); err != nil {
 		return nil, err
@@ -293,7 +294,7 @@ func (c *Crypto) EncryptStreamWithPadding(in io.Reader, out io.Writer, padding b
 	if err != nil {
 		return err
 	}
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	dst := NewEncryptWriter(NopWriteCloser(out), cipher)
 	if err := copyClose(dst, in); err != nil {
@@ -306,7 +307,7 @@ func (c *Crypto) EncryptStreamWithPadding(in io.Reader, out io.Writer, padding b
 func (c *Crypto) DecryptStream(in io.Reader, out io.Writer, key PrivateKey) (err error) {
 
 	cipher := c.setupCipher(false)
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	if err = cipher.StartDecryptionWithKey(key.Identifier(), key.Unwrap(), nil); err != nil {
 		return err
@@ -323,7 +324,7 @@ func (c *Crypto) DecryptStream(in io.Reader, out io.Writer, key PrivateKey) (err
 func (c *Crypto) Sign(data []byte, signer PrivateKey) ([]byte, error) {
 	s := foundation.NewSigner()
 	h := foundation.NewSha512()
-	defer delete(s, h)
+	defer deleteObjects(s, h)
 
 	s.SetRandom(random)
 	s.SetHash(h)
@@ -336,7 +337,7 @@ func (c *Crypto) Sign(data []byte, signer PrivateKey) ([]byte, error) {
 func (c *Crypto) VerifySignature(data []byte, signature []byte, key PublicKey) error {
 
 	v := foundation.NewVerifier()
-	defer delete(v)
+	defer deleteObjects(v)
 
 	if err := v.Reset(signature); err != nil {
 		return err
@@ -352,7 +353,7 @@ func (c *Crypto) VerifySignature(data []byte, signature []byte, key PublicKey) e
 func (c *Crypto) SignStream(in io.Reader, signer PrivateKey) ([]byte, error) {
 	s := foundation.NewSigner()
 	h := foundation.NewSha512()
-	defer delete(s, h)
+	defer deleteObjects(s, h)
 
 	s.SetRandom(random)
 	s.SetHash(h)
@@ -366,7 +367,7 @@ func (c *Crypto) SignStream(in io.Reader, signer PrivateKey) ([]byte, error) {
 
 func (c *Crypto) VerifyStream(in io.Reader, signature []byte, key PublicKey) error {
 	v := foundation.NewVerifier()
-	defer delete(v)
+	defer deleteObjects(v)
 
 	if err := v.Reset(signature); err != nil {
 		return err
@@ -386,17 +387,27 @@ func (c *Crypto) SignThenEncrypt(data []byte, signer PrivateKey, recipients ...P
 }
 
 func (c *Crypto) SignThenEncryptWithPadding(data []byte, signer PrivateKey, padding bool, recipients ...PublicKey) ([]byte, error) {
+	return c.SignThenEncryptWithSigners(data, []PrivateKey{signer}, padding, recipients...)
+}
+
+// SignThenEncryptWithSigners encrypts data signed by every key of signers.
+func (c *Crypto) SignThenEncryptWithSigners(data []byte, signers []PrivateKey, padding bool, recipients ...PublicKey) ([]byte, error) {
+	if len(signers) == 0 {
+		return nil, ErrUnsupportedParameter
+	}
 	cipher, err := c.setupEncryptCipher(recipients, padding)
 	if err != nil {
 		return nil, err
 	}
 	h := foundation.NewSha512()
 
-	defer delete(cipher, h)
+	defer deleteObjects(cipher, h)
 
 	cipher.SetSignerHash(h)
-	if err = cipher.AddSigner(signer.Identifier(), signer.Unwrap()); err != nil {
-		return nil, err
+	for _, signer := range signers {
+		if err = cipher.AddSigner(signer.Identifier(), signer.Unwrap()); err != nil {
+			return nil, err
+		}
 	}
 	if err = cipher.StartSignedEncryption(uint(len(data))); err != nil {
 		return nil, err
@@ -426,21 +437,41 @@ func (c *Crypto) DecryptThenVerify(
 	decryptionKey PrivateKey,
 	verifierKeys ...PublicKey,
 ) (_ []byte, err error) {
+	plaintext, _, err := c.DecryptThenVerifySigners(data, decryptionKey, 1, verifierKeys...)
+	return plaintext, err
+}
+
+// DecryptThenVerifySigners decrypts data and verifies its signatures against
+// verifierKeys. With SignQuorumAll every signature must be made by one of
+// verifierKeys, otherwise at least quorum signers must be verified and
+// signatures of unknown signers are ignored. A signature of a known signer
+// that does not verify is always an error. The identifiers of verified
+// signers are returned.
+func (c *Crypto) DecryptThenVerifySigners(
+	data []byte,
+	decryptionKey PrivateKey,
+	quorum int,
+	verifierKeys ...PublicKey,
+) (_ []byte, verifiedSigners [][]byte, err error) {
+	if quorum < 0 {
+		return nil, nil, ErrUnsupportedParameter
+	}
 	cipher := c.setupCipher(false)
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	if err := cipher.StartDecryptionWithKey(decryptionKey.Identifier(), decryptionKey.Unwrap(), nil); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	buffer := bytes.NewBuffer(nil)
 	if _, err := io.Copy(buffer, NewDecryptReader(bytes.NewReader(data), cipher)); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if err := c.verifyCipherSign(cipher, verifierKeys); err != nil {
-		return nil, err
+	verifiedSigners, err = c.verifyCipherSign(cipher, verifierKeys, quorum)
+	if err != nil {
+		return nil, nil, err
 	}
-	return buffer.Bytes(), nil
+	return buffer.Bytes(), verifiedSigners, nil
 }
 
 func (c *Crypto) SignThenEncryptStream(
@@ -469,7 +500,7 @@ func (c *Crypto) SignThenEncryptStreamWithPadding(
 		cipher *foundation.RecipientCipher
 		h      foundation.Hash
 	)
-	defer delete(cipher, h)
+	defer deleteObjects(cipher, h)
 
 	cipher, err = c.setupEncryptCipher(recipients, padding)
 	if err != nil {
@@ -509,7 +540,7 @@ func (c *Crypto) DecryptThenVerifyStream(
 ) error {
 
 	cipher := c.setupCipher(false)
-	defer delete(cipher)
+	defer deleteObjects(cipher)
 
 	if err := cipher.StartDecryptionWithKey(decryptionKey.Identifier(), decryptionKey.Unwrap(), nil); err != nil {
 		return err
@@ -519,7 +550,8 @@ func (c *Crypto) DecryptThenVerifyStream(
 		return err
 	}
 
-	return c.verifyCipherSign(cipher, verifierKeys)
+	_, err := c.verifyCipherSign(cipher, verifierKeys, 1)
+	return err
 }
 
 func (c *Crypto) SignAndEncrypt(data []byte, signer PrivateKey, recipients ...PublicKey) (_ []byte, err error) {
@@ -527,25 +559,35 @@ func (c *Crypto) SignAndEncrypt(data []byte, signer PrivateKey, recipients ...Pu
 }
 
 func (c *Crypto) SignAndEncryptWithPadding(data []byte, signer PrivateKey, padding bool, recipients ...PublicKey) (_ []byte, err error) {
+	return c.SignAndEncryptWithSigners(data, []PrivateKey{signer}, padding, recipients...)
+}
+
+// SignAndEncryptWithSigners encrypts data with a signature of every key of
+// signers stored in the message info.
+func (c *Crypto) SignAndEncryptWithSigners(data []byte, signers []PrivateKey, padding bool, recipients ...PublicKey) (_ []byte, err error) {
+	if len(signers) == 0 {
+		return nil, ErrUnsupportedParameter
+	}
 	var (
 		cipher *foundation.RecipientCipher
 		params *foundation.MessageInfoCustomParams
 	)
-	defer delete(cipher, params)
+	defer deleteObjects(cipher, params)
 
 	cipher, err = c.setupEncryptCipher(recipients, padding)
 	if err != nil {
 		return nil, err
 	}
 
-	sign, err := c.Sign(data, signer)
-	if err != nil {
-		return nil, err
-	}
-
 	params = cipher.CustomParams()
-	params.AddData(signatureKey, sign)
-	params.AddData(signerIDKey, signer.Identifier())
+	for i, signer := range signers {
+		sign, err := c.Sign(data, signer)
+		if err != nil {
+			return nil, err
+		}
+		params.AddData(signerParamKey(signatureKey, i), sign)
+		params.AddData(signerParamKey(signerIDKey, i), signer.Identifier())
+	}
 
 	if err := cipher.StartEncryption(); err != nil {
 		return nil, err
@@ -564,80 +606,105 @@ func (c *Crypto) SignAndEncryptWithPadding(data []byte, signer PrivateKey, paddi
 }
 
 func (c *Crypto) DecryptAndVerify(data []byte, decryptionKey PrivateKey, verifierKeys ...PublicKey) (_ []byte, err error) {
-	var (
-		cipher *foundation.RecipientCipher
-		params *foundation.MessageInfoCustomParams
-	)
-	defer delete(cipher, params)
-
-	cipher = c.setupCipher(false)
-	if err = cipher.StartDecryptionWithKey(decryptionKey.Identifier(), decryptionKey.Unwrap(), nil); err != nil {
-		return nil, err
-	}
+	plaintext, _, err := c.DecryptAndVerifySigners(data, decryptionKey, 1, verifierKeys...)
+	return plaintext, err
+}
 
+// DecryptAndVerifySigners decrypts data produced by SignAndEncrypt or
+// SignAndEncryptWithSigners and verifies its signatures with the same rules
+// as DecryptThenVerifySigners.
+func (c *Crypto) DecryptAndVerifySigners(
+	data []byte,
+	decryptionKey PrivateKey,
+	quorum int,
+	verifierKeys ...PublicKey,
+) (_ []byte, verifiedSigners [][]byte, err error) {
 	buffer := bytes.NewBuffer(nil)
-	if _, err = io.Copy(buffer, NewDecryptReader(bytes.NewReader(data), cipher)); err != nil {
-		return nil, err
-	}
-
-	params = cipher.CustomParams()
-	signerID, err := params.FindData(signerIDKey)
+	verifiedSigners, err = c.DecryptAndVerifyStreamSigners(bytes.NewReader(data), buffer, decryptionKey, quorum, verifierKeys...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return buffer.Bytes(), verifiedSigners, nil
+}
 
-	sign, err := params.FindData(signatureKey)
-	if err != nil {
-		return nil, err
+func (c *Crypto) Hash(data []byte, t HashType) ([]byte, error) {
+	hf, ok := hashMap[t]
+	if !ok {
+		h, err := c.NewHasher(t)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(data)
+		return h.Sum(nil), nil
 	}
+	hash := hf().Hash(data)
+
+	return hash, nil
+}
 
-	k, err := findVerifyKey(signerID, verifierKeys)
+// HashStream returns the hash of everything read from in.
+func (c *Crypto) HashStream(in io.Reader, t HashType) ([]byte, error) {
+	h, err := c.NewHasher(t)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := c.VerifySignature(buffer.Bytes(), sign, k); err != nil {
+	if _, err = io.Copy(h, in); err != nil {
 		return nil, err
 	}
-	return buffer.Bytes(), nil
+	return h.Sum(nil), nil
 }
 
-func (c *Crypto) Hash(data []byte, t HashType) ([]byte, error) {
-	hf, ok := hashMap[t]
+// NewHasher returns a hash.Hash of the hash type t; its Sum is equal to the
+// result of Hash for the data written to it.
+func (c *Crypto) NewHasher(t HashType) (hash.Hash, error) {
+	hf, ok := stdHashMap[t]
 	if !ok {
 		return nil, ErrUnsupportedHashType
 	}
-	hash := hf().Hash(data)
-
-	return hash, nil
+	return hf(), nil
 }
 
-func (c *Crypto) verifyCipherSign(cipher *foundation.RecipientCipher, verifierKeys []PublicKey) error {
-	var (
-		signerInfoList *foundation.SignerInfoList
-		signInfo       *foundation.SignerInfo
-	)
-	defer delete(signerInfoList, signInfo)
-
+func (c *Crypto) verifyCipherSign(cipher *foundation.RecipientCipher, verifierKeys []PublicKey, quorum int) ([][]byte, error) {
 	if !cipher.IsDataSigned() {
-		return ErrSignNotFound
+		return nil, ErrSignNotFound
 	}
 
-	signerInfoList = cipher.SignerInfos()
-	if !signerInfoList.HasItem() {
-		return ErrSignNotFound
-	}
+	var (
+		lists      []*foundation.SignerInfoList
+		infos      []*foundation.SignerInfo
+		signatures []messageSignature
+	)
+	defer func() {
+		for _, l := range lists {
+			deleteObjects(l)
+		}
+		for _, i := range infos {
+			deleteObjects(i)
+		}
+	}()
 
-	signInfo = signerInfoList.Item()
-	k, err := findVerifyKey(signInfo.SignerId(), verifierKeys)
-	if err != nil {
-		return err
-	}
+	l := cipher.SignerInfos()
+	for {
+		lists = append(lists, l)
+		if !l.HasItem() {
+			break
+		}
 
-	if cipher.VerifySignerInfo(signInfo, k.Unwrap()) {
-		return nil
+		info := l.Item()
+		infos = append(infos, info)
+		signatures = append(signatures, messageSignature{
+			signerID: info.SignerId(),
+			verify: func(k PublicKey) bool {
+				return cipher.VerifySignerInfo(info, k.Unwrap())
+			},
+		})
+		if !l.HasNext() {
+			break
+		}
+		l = l.Next()
 	}
-	return ErrSignVerification
+
+	return verifySignatures(signatures, verifierKeys, quorum)
 }
 
 func findVerifyKey(signerID []byte, verifierKeys []PublicKey) (PublicKey, error) {
@@ -655,7 +722,7 @@ const paddingLen uint = 160
 func (c *Crypto) setupCipher(padding bool) *foundation.RecipientCipher {
 	aesGcm := foundation.NewAes256Gcm()
 	cipher := foundation.NewRecipientCipher()
-	defer delete(aesGcm)
+	defer deleteObjects(aesGcm)
 
 	cipher.SetEncryptionCipher(aesGcm)
 	cipher.SetRandom(random)
@@ -666,7 +733,7 @@ func (c *Crypto) setupCipher(padding bool) *foundation.RecipientCipher {
 		cipher.SetEncryptionPadding(padding)
 		paddingParams := foundation.NewPaddingParamsWithConstraints(paddingLen, paddingLen)
 		cipher.SetPaddingParams(paddingParams)
-		delete(padding)
+		deleteObjects(padding)
 	}
 	return cipher
 }
@@ -33,7 +33,11 @@
 package crypto_test
 
 import (
+	"bytes"
 	"crypto/rand"
+	"fmt"
+	"io"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -276,3 +280,50 @@ func Benchmark1000Recipients(b *testing.B) {
 		// fmt.Println(len(ct))
 	}
 }
+
+func benchmarkEncryptLargeStream(b *testing.B, encrypt func(vcrypto *crypto.Crypto, in io.Reader, pk crypto.PublicKey) error) {
+	vcrypto := &crypto.Crypto{}
+
+	// make random data
+	data := make([]byte, 16<<20)
+	rand.Read(data)
+
+	encryptSk, err := vcrypto.GenerateKeypair()
+	require.NoError(b, err)
+	encryptPk := encryptSk.PublicKey()
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err = encrypt(vcrypto, bytes.NewReader(data), encryptPk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncryptStream16MiB measures the EncryptStream format, which has no
+// parallel mode; compare EncryptChunkedStreamParallel with BenchmarkEncryptChunkedStream16MiB.
+func BenchmarkEncryptStream16MiB(b *testing.B) {
+	benchmarkEncryptLargeStream(b, func(vcrypto *crypto.Crypto, in io.Reader, pk crypto.PublicKey) error {
+		return vcrypto.EncryptStream(in, io.Discard, pk)
+	})
+}
+
+func BenchmarkEncryptChunkedStream16MiB(b *testing.B) {
+	benchmarkEncryptLargeStream(b, func(vcrypto *crypto.Crypto, in io.Reader, pk crypto.PublicKey) error {
+		return vcrypto.EncryptChunkedStream(in, io.Discard, pk)
+	})
+}
+
+// BenchmarkEncryptChunkedStreamParallel16MiB encrypts to the same format as
+// BenchmarkEncryptChunkedStream16MiB with different numbers of workers.
+func BenchmarkEncryptChunkedStreamParallel16MiB(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, runtime.GOMAXPROCS(0)} {
+		workers := workers
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			benchmarkEncryptLargeStream(b, func(vcrypto *crypto.Crypto, in io.Reader, pk crypto.PublicKey) error {
+				return vcrypto.EncryptChunkedStreamParallel(in, io.Discard, workers, pk)
+			})
+		})
+	}
+}
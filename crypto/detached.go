@@ -0,0 +1,338 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"io"
+	"time"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+const detachedSignatureVersion = 1
+
+// detachedSignatureHash is the hash used by SignStreamDetached
+const detachedSignatureHash = Sha512
+
+// DetachedSignature is a signature kept apart from the signed data together
+// with the signer identifier, key type, hash and creation time. The metadata
+// is covered by the signature.
+type DetachedSignature struct {
+	SignerID  []byte
+	KeyType   KeyType
+	Hash      HashType
+	CreatedAt time.Time
+	Signature []byte
+}
+
+// detachedSignatureInfo is the signed metadata, its DER encoding precedes the data
+type detachedSignatureInfo struct {
+	Version   int
+	SignerID  []byte
+	KeyType   detachedKeyType
+	Hash      asn1.ObjectIdentifier
+	CreatedAt time.Time `asn1:"generalized"`
+}
+
+// detachedKeyType encodes a KeyType as its kind followed by the bit length for
+// RSA or by the ids from detachedAlgorithmIDs of its algorithms, 0 for AlgNone
+type detachedKeyType struct {
+	Kind   int
+	Params []int
+}
+
+const (
+	detachedKeySimple   = 1 // Params: algorithm
+	detachedKeyRSA      = 2 // Params: bit length
+	detachedKeyHybrid   = 3 // Params: cipher, post-quantum cipher
+	detachedKeyCompound = 4 // Params: cipher, post-quantum cipher, signer, post-quantum signer
+)
+
+// detachedAlgorithmIDs are the algorithm ids of the detached signature format.
+// They must never change; new algorithms get new ids.
+var detachedAlgorithmIDs = map[Algorithm]int{
+	AlgEd25519:         1,
+	AlgCurve25519:      2,
+	AlgP256r1:          3,
+	AlgFalcon:          4,
+	AlgMlKem768:        5,
+	AlgMlDsa65:         6,
+	AlgMlKem1024:       7,
+	AlgMlDsa44:         8,
+	AlgMlDsa87:         9,
+	AlgSlhDsaSha2_128s: 10,
+	AlgSlhDsaSha2_256s: 11,
+}
+
+// detachedHashOIDs are the NIST object identifiers of the hashes a detached signature may name
+var detachedHashOIDs = map[HashType]asn1.ObjectIdentifier{
+	Sha224: {2, 16, 840, 1, 101, 3, 4, 2, 4},
+	Sha256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+	Sha384: {2, 16, 840, 1, 101, 3, 4, 2, 2},
+	Sha512: {2, 16, 840, 1, 101, 3, 4, 2, 3},
+}
+
+type detachedSignatureASN1 struct {
+	Info      detachedSignatureInfo
+	Signature []byte
+}
+
+func (c *Crypto) SignDetached(data []byte, signer PrivateKey) (*DetachedSignature, error) {
+	return c.SignStreamDetached(bytes.NewReader(data), signer)
+}
+
+// SignStreamDetached signs the data read from in and the signature metadata.
+func (c *Crypto) SignStreamDetached(in io.Reader, signer PrivateKey) (*DetachedSignature, error) {
+	sig := &DetachedSignature{
+		SignerID:  signer.Identifier(),
+		KeyType:   signer.KeyType(),
+		Hash:      detachedSignatureHash,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	info, err := sig.info()
+	if err != nil {
+		return nil, err
+	}
+
+	s := foundation.NewSigner()
+	h := hashMap[detachedSignatureHash]()
+	defer deleteObjects(s, h)
+
+	s.SetRandom(random)
+	s.SetHash(h)
+	s.Reset()
+	s.AppendData(info)
+	if _, err = io.Copy(&appenderWriter{s}, in); err != nil {
+		return nil, err
+	}
+
+	if sig.Signature, err = s.Sign(signer.Unwrap()); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// VerifyDetached verifies sig with the candidate key matching its signer
+// identifier and returns that key.
+func (c *Crypto) VerifyDetached(data []byte, sig *DetachedSignature, candidates ...PublicKey) (PublicKey, error) {
+	return c.VerifyStreamDetached(bytes.NewReader(data), sig, candidates...)
+}
+
+func (c *Crypto) VerifyStreamDetached(in io.Reader, sig *DetachedSignature, candidates ...PublicKey) (PublicKey, error) {
+	key, err := findVerifyKey(sig.SignerID, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if key.KeyType() != sig.KeyType {
+		return nil, ErrSignVerification
+	}
+	if sig.Hash != detachedSignatureHash {
+		return nil, ErrUnsupportedDetachedSignatureHash
+	}
+	info, err := sig.info()
+	if err != nil {
+		return nil, err
+	}
+
+	v := foundation.NewVerifier()
+	defer deleteObjects(v)
+
+	if err = v.Reset(sig.Signature); err != nil {
+		return nil, err
+	}
+	v.AppendData(info)
+	if _, err = io.Copy(&appenderWriter{v}, in); err != nil {
+		return nil, err
+	}
+
+	if !v.Verify(key.Unwrap()) {
+		return nil, ErrSignVerification
+	}
+	return key, nil
+}
+
+// Marshal serializes the signature to DER.
+func (s *DetachedSignature) Marshal() ([]byte, error) {
+	info, err := s.asn1Info()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(detachedSignatureASN1{
+		Info:      info,
+		Signature: s.Signature,
+	})
+}
+
+// ParseDetachedSignature parses a signature serialized by DetachedSignature.Marshal.
+func ParseDetachedSignature(data []byte) (*DetachedSignature, error) {
+	var raw detachedSignatureASN1
+	rest, err := asn1.Unmarshal(data, &raw)
+	if err != nil || len(rest) != 0 {
+		return nil, ErrInvalidDetachedSignature
+	}
+	if raw.Info.Version != detachedSignatureVersion {
+		return nil, ErrUnsupportedDetachedSignatureVersion
+	}
+	kt, err := raw.Info.KeyType.keyType()
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseDetachedHash(raw.Info.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &DetachedSignature{
+		SignerID:  raw.Info.SignerID,
+		KeyType:   kt,
+		Hash:      h,
+		CreatedAt: raw.Info.CreatedAt.UTC(),
+		Signature: raw.Signature,
+	}, nil
+}
+
+func (s *DetachedSignature) asn1Info() (detachedSignatureInfo, error) {
+	kt, err := newDetachedKeyType(s.KeyType)
+	if err != nil {
+		return detachedSignatureInfo{}, err
+	}
+	oid, ok := detachedHashOIDs[s.Hash]
+	if !ok {
+		return detachedSignatureInfo{}, ErrUnsupportedDetachedSignatureHash
+	}
+	return detachedSignatureInfo{
+		Version:   detachedSignatureVersion,
+		SignerID:  s.SignerID,
+		KeyType:   kt,
+		Hash:      oid,
+		CreatedAt: s.CreatedAt.UTC(),
+	}, nil
+}
+
+func (s *DetachedSignature) info() ([]byte, error) {
+	info, err := s.asn1Info()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(info)
+}
+
+func newDetachedKeyType(kt KeyType) (detachedKeyType, error) {
+	ids := func(algs ...Algorithm) ([]int, error) {
+		params := make([]int, len(algs))
+		for i, a := range algs {
+			if a == AlgNone {
+				continue
+			}
+			id, ok := detachedAlgorithmIDs[a]
+			if !ok {
+				return nil, ErrUnsupportedKeyType
+			}
+			params[i] = id
+		}
+		return params, nil
+	}
+
+	var (
+		d   detachedKeyType
+		err error
+	)
+	switch {
+	case kt.rsaBitlen > 0:
+		d.Kind, d.Params = detachedKeyRSA, []int{int(kt.rsaBitlen)}
+	case kt.simple != AlgNone:
+		d.Kind = detachedKeySimple
+		d.Params, err = ids(kt.simple)
+	case kt.cipher != AlgNone && kt.signer != AlgNone:
+		d.Kind = detachedKeyCompound
+		d.Params, err = ids(kt.cipher, kt.pqCipher, kt.signer, kt.pqSigner)
+	case kt.cipher != AlgNone && kt.pqCipher != AlgNone:
+		d.Kind = detachedKeyHybrid
+		d.Params, err = ids(kt.cipher, kt.pqCipher)
+	default:
+		err = ErrUnsupportedKeyType
+	}
+	return d, err
+}
+
+// keyType is the inverse of newDetachedKeyType
+func (d detachedKeyType) keyType() (KeyType, error) {
+	algs := make([]Algorithm, len(d.Params))
+	if d.Kind != detachedKeyRSA {
+		for i, id := range d.Params {
+			if id == 0 {
+				continue
+			}
+			algs[i] = algorithmByDetachedID(id)
+			if algs[i] == AlgNone {
+				return KeyType{}, ErrUnsupportedKeyType
+			}
+		}
+	}
+
+	switch {
+	case d.Kind == detachedKeyRSA && len(d.Params) == 1 && d.Params[0] > 0:
+		return RsaKey(uint(d.Params[0])), nil
+	case d.Kind == detachedKeySimple && len(algs) == 1 && algs[0] != AlgNone:
+		return KeyType{simple: algs[0]}, nil
+	case d.Kind == detachedKeyHybrid && len(algs) == 2 && algs[0] != AlgNone && algs[1] != AlgNone:
+		return HybridKEM(algs[0], algs[1]), nil
+	case d.Kind == detachedKeyCompound && len(algs) == 4 && algs[0] != AlgNone && algs[2] != AlgNone:
+		return CompoundKey(algs[0], algs[1], algs[2], algs[3]), nil
+	default:
+		return KeyType{}, ErrUnsupportedKeyType
+	}
+}
+
+func algorithmByDetachedID(id int) Algorithm {
+	for a, v := range detachedAlgorithmIDs {
+		if v == id {
+			return a
+		}
+	}
+	return AlgNone
+}
+
+func parseDetachedHash(oid asn1.ObjectIdentifier) (HashType, error) {
+	for h, v := range detachedHashOIDs {
+		if v.Equal(oid) {
+			return h, nil
+		}
+	}
+	return DefaultHashType, ErrUnsupportedDetachedSignatureHash
+}
@@ -0,0 +1,105 @@
+package crypto
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetachedKeyType(t *testing.T) {
+	types := []KeyType{
+		RsaKey(2048), P256r1, Curve25519, Ed25519, MlKem1024,
+		Curve25519Ed25519,
+		Curve25519MlKem768Ed25519Falcon,
+		Curve25519MlKem768Ed25519MlDsa65,
+		Curve25519MlKem1024Ed25519SlhDsaSha2_256s,
+		HybridKEM(AlgCurve25519, AlgMlKem768),
+		CompoundKey(AlgCurve25519, AlgNone, AlgEd25519, AlgMlDsa65),
+	}
+	for _, kt := range types {
+		d, err := newDetachedKeyType(kt)
+		require.NoError(t, err)
+		parsed, err := d.keyType()
+		require.NoError(t, err)
+		require.Equal(t, kt, parsed)
+	}
+
+	// the encoding does not depend on the order of the Algorithm constants
+	d, err := newDetachedKeyType(Curve25519MlKem768Ed25519MlDsa65)
+	require.NoError(t, err)
+	require.Equal(t, detachedKeyType{Kind: detachedKeyCompound, Params: []int{2, 5, 1, 6}}, d)
+
+	_, err = newDetachedKeyType(KeyType{})
+	require.Equal(t, ErrUnsupportedKeyType, err)
+
+	invalid := []detachedKeyType{
+		{},
+		{Kind: detachedKeySimple},
+		{Kind: detachedKeySimple, Params: []int{0}},
+		{Kind: detachedKeySimple, Params: []int{1000}},
+		{Kind: detachedKeyRSA, Params: []int{0}},
+		{Kind: detachedKeyHybrid, Params: []int{2}},
+		{Kind: detachedKeyHybrid, Params: []int{2, 0}},
+		{Kind: detachedKeyCompound, Params: []int{2, 0, 0, 0}},
+		{Kind: 5, Params: []int{1}},
+	}
+	for _, d := range invalid {
+		_, err := d.keyType()
+		require.Equal(t, ErrUnsupportedKeyType, err, d)
+	}
+}
+
+func TestDetachedSignatureMarshal(t *testing.T) {
+	sig := &DetachedSignature{
+		SignerID:  []byte{1, 2, 3},
+		KeyType:   Curve25519MlKem768Ed25519Falcon,
+		Hash:      Sha512,
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Signature: []byte{4, 5, 6},
+	}
+	data, err := sig.Marshal()
+	require.NoError(t, err)
+
+	parsed, err := ParseDetachedSignature(data)
+	require.NoError(t, err)
+	require.Equal(t, sig, parsed)
+
+	_, err = ParseDetachedSignature(append(data, 0))
+	require.Equal(t, ErrInvalidDetachedSignature, err)
+
+	info, err := sig.asn1Info()
+	require.NoError(t, err)
+	raw := detachedSignatureASN1{Info: info, Signature: sig.Signature}
+	raw.Info.Version = detachedSignatureVersion + 1
+	data, err = asn1.Marshal(raw)
+	require.NoError(t, err)
+	_, err = ParseDetachedSignature(data)
+	require.Equal(t, ErrUnsupportedDetachedSignatureVersion, err)
+
+	raw.Info.Version = detachedSignatureVersion
+	raw.Info.Hash = asn1.ObjectIdentifier{1, 2, 3}
+	data, err = asn1.Marshal(raw)
+	require.NoError(t, err)
+	_, err = ParseDetachedSignature(data)
+	require.Equal(t, ErrUnsupportedDetachedSignatureHash, err)
+
+	sig.Hash = Blake2b512
+	_, err = sig.Marshal()
+	require.Equal(t, ErrUnsupportedDetachedSignatureHash, err)
+}
+
+func TestDetachedSignatureMarshal_Vector(t *testing.T) {
+	sig := &DetachedSignature{
+		SignerID:  []byte{1, 2, 3},
+		KeyType:   Curve25519MlKem768Ed25519MlDsa65,
+		Hash:      Sha512,
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Signature: []byte{4, 5, 6},
+	}
+	data, err := sig.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, "303e303702010104030102033011020104300c0201020201050201010201060609608648016503040203180f32303236313031383132303030305a0403040506", hex.EncodeToString(data))
+}
@@ -43,6 +43,7 @@ import (
 var (
 	ErrUnsupportedKeyType  = errors.New("unsupported key types")
 	ErrUnsupportedHashType = errors.New("unsupported hash types")
+	ErrKeyTypeMismatch     = errors.New("key types do not match")
 	ErrStreamSizeIncorrect = errors.New("stream size should be greater 0")
 	ErrInvalidSeedSize     = fmt.Errorf("invalid seed size (expected %d < x < %d)",
 		foundation.KeyMaterialRngKeyMaterialLenMin,
@@ -51,4 +52,36 @@ var (
 	ErrUnsupportedParameter = errors.New("unsupported function parameter")
 	ErrSignVerification     = errors.New("sign verification failed")
 	ErrSignNotFound         = errors.New("signature not found")
+	ErrSignQuorumNotReached = errors.New("not enough signers verified")
+	ErrHMACVerification     = errors.New("HMAC verification failed")
+
+	ErrPasswordIsEmpty               = errors.New("password is empty")
+	ErrUnsupportedPasswordKDF        = errors.New("unsupported password key derivation function")
+	ErrInvalidKeyEnvelope            = errors.New("invalid password protected key")
+	ErrUnsupportedKeyEnvelopeVersion = errors.New("unsupported password protected key version")
+	ErrInvalidPassword               = errors.New("invalid password or corrupted key")
+
+	ErrInvalidMessageHeader = errors.New("invalid encrypted message header")
+	ErrKeyNotFound          = errors.New("private key not found")
+	ErrNoRecipients         = errors.New("message would have no recipients")
+
+	ErrInvalidDetachedSignature            = errors.New("invalid detached signature")
+	ErrUnsupportedDetachedSignatureVersion = errors.New("unsupported detached signature version")
+	ErrUnsupportedDetachedSignatureHash    = errors.New("unsupported detached signature hash")
+
+	ErrUnsupportedSymmetricAlgorithm = errors.New("unsupported symmetric algorithm")
+	ErrInvalidSymmetricKey           = errors.New("invalid symmetric key length")
+	ErrSymmetricAuthentication       = errors.New("symmetric decryption failed: message authentication failed")
+	ErrInvalidSymmetricStream        = errors.New("invalid symmetric stream")
+	ErrSymmetricStreamTooLong        = errors.New("symmetric stream has too many chunks")
+	ErrSymmetricStreamClosed         = errors.New("symmetric stream is closed")
+
+	ErrInvalidChunkedMessage            = errors.New("invalid chunked encrypted message")
+	ErrUnsupportedChunkedMessageVersion = errors.New("unsupported chunked encrypted message version")
+
+	ErrInvalidEncapsulatedKey            = errors.New("invalid encapsulated key")
+	ErrUnsupportedEncapsulatedKeyVersion = errors.New("unsupported encapsulated key version")
+
+	ErrPEMBlockNotFound   = errors.New("PEM key block not found")
+	ErrPEMKeyTypeMismatch = errors.New("PEM Key-Type header does not match the key")
 )
@@ -33,6 +33,13 @@
 package crypto
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+
 	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
 )
 
@@ -44,6 +51,10 @@ const (
 	Sha256
 	Sha384
 	Sha512
+	Sha3_256
+	Sha3_512
+	Blake2b256
+	Blake2b512
 )
 
 var hashMap = map[HashType]func() foundation.Hash{
@@ -63,3 +74,23 @@ var hashMap = map[HashType]func() foundation.Hash{
 		return foundation.NewSha512()
 	},
 }
+
+// stdHashMap holds the Go implementations of the hash types, used where a
+// hash.Hash is required and for the hash types missing in hashMap
+var stdHashMap = map[HashType]func() hash.Hash{
+	DefaultHashType: sha512.New,
+	Sha224:          sha256.New224,
+	Sha256:          sha256.New,
+	Sha384:          sha512.New384,
+	Sha512:          sha512.New,
+	Sha3_256:        sha3.New256,
+	Sha3_512:        sha3.New512,
+	Blake2b256: func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	},
+	Blake2b512: func() hash.Hash {
+		h, _ := blake2b.New512(nil)
+		return h
+	},
+}
@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestHashStream(t *testing.T) {
+	c := &Crypto{}
+	data := bytes.Repeat([]byte("abc"), 100000)
+
+	sha3256 := sha3.Sum256(data)
+	sha3512 := sha3.Sum512(data)
+	blake256 := blake2b.Sum256(data)
+	blake512 := blake2b.Sum512(data)
+	sha2256 := sha256.Sum256(data)
+	expected := map[HashType][]byte{
+		Sha3_256:   sha3256[:],
+		Sha3_512:   sha3512[:],
+		Blake2b256: blake256[:],
+		Blake2b512: blake512[:],
+		Sha256:     sha2256[:],
+	}
+	for ht, digest := range expected {
+		h, err := c.HashStream(bytes.NewReader(data), ht)
+		require.NoError(t, err)
+		require.Equal(t, digest, h)
+
+		hasher, err := c.NewHasher(ht)
+		require.NoError(t, err)
+		hasher.Write(data[:5])
+		hasher.Write(data[5:])
+		require.Equal(t, digest, hasher.Sum(nil))
+
+		if ht != Sha256 {
+			h, err = c.Hash(data, ht)
+			require.NoError(t, err)
+			require.Equal(t, digest, h)
+		}
+	}
+
+	mac := hmac.New(sha3.New256, []byte("key"))
+	mac.Write(data)
+	h, err := c.HMAC([]byte("key"), data, Sha3_256)
+	require.NoError(t, err)
+	require.Equal(t, mac.Sum(nil), h)
+
+	_, err = c.HashStream(bytes.NewReader(data), HashType(100))
+	require.ErrorIs(t, err, ErrUnsupportedHashType)
+}
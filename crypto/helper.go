@@ -41,7 +41,8 @@ type deleter interface {
 	Delete()
 }
 
-func delete(lst ...deleter) {
+// deleteObjects frees the underlying C objects of lst, skipping nil ones
+func deleteObjects(lst ...deleter) {
 	for _, i := range lst {
 		if i != nil {
 			i.Delete()
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"crypto/hmac"
+	"hash"
+	"io"
+)
+
+// HMAC returns the HMAC of data keyed with key and built on the hash type t.
+func (c *Crypto) HMAC(key, data []byte, t HashType) ([]byte, error) {
+	w, err := c.NewHMACWriter(key, t)
+	if err != nil {
+		return nil, err
+	}
+	w.Write(data)
+	return w.Sum(), nil
+}
+
+// VerifyHMAC compares mac with the HMAC of data in constant time.
+func (c *Crypto) VerifyHMAC(key, data, mac []byte, t HashType) error {
+	w, err := c.NewHMACWriter(key, t)
+	if err != nil {
+		return err
+	}
+	w.Write(data)
+	return w.Verify(mac)
+}
+
+// HMACStream returns the HMAC of everything read from in.
+func (c *Crypto) HMACStream(key []byte, in io.Reader, t HashType) ([]byte, error) {
+	w, err := c.NewHMACWriter(key, t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(w, in); err != nil {
+		return nil, err
+	}
+	return w.Sum(), nil
+}
+
+// NewHMACWriter returns a writer that computes the HMAC of the data written to it.
+func (c *Crypto) NewHMACWriter(key []byte, t HashType) (*HMACWriter, error) {
+	hf, ok := stdHashMap[t]
+	if !ok {
+		return nil, ErrUnsupportedHashType
+	}
+	if len(key) == 0 {
+		return nil, ErrUnsupportedParameter
+	}
+	return &HMACWriter{h: hmac.New(hf, key)}, nil
+}
+
+// HMACWriter computes an HMAC incrementally. It is not safe for concurrent use.
+type HMACWriter struct {
+	h hash.Hash
+}
+
+// Write adds d to the HMAC input. It never returns an error.
+func (w *HMACWriter) Write(d []byte) (int, error) {
+	return w.h.Write(d)
+}
+
+// Sum returns the HMAC of the data written so far.
+func (w *HMACWriter) Sum() []byte {
+	return w.h.Sum(nil)
+}
+
+// Verify compares mac with the HMAC of the data written so far in constant time.
+func (w *HMACWriter) Verify(mac []byte) error {
+	if !hmac.Equal(w.h.Sum(nil), mac) {
+		return ErrHMACVerification
+	}
+	return nil
+}
+
+// Reset discards the data written so far, keeping the key.
+func (w *HMACWriter) Reset() {
+	w.h.Reset()
+}
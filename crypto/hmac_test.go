@@ -0,0 +1,62 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMAC(t *testing.T) {
+	c := &Crypto{}
+	// RFC 4231, test case 2
+	key := []byte("Jefe")
+	data := []byte("what do ya want for nothing?")
+	vectors := map[HashType]string{
+		Sha224: "a30e01098bc6dbbf45690f3a7e9e6d0f8bbea2a39e6148008fd05e44",
+		Sha256: "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		Sha384: "af45d2e376484031617f78d2b58a6b1b9c7ef464f5a01b47e42ec3736322445e8e2240ca5e69e2c78b3239ecfab21649",
+		Sha512: "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+	}
+	for ht, expected := range vectors {
+		mac, err := c.HMAC(key, data, ht)
+		require.NoError(t, err)
+		require.Equal(t, mustHex(t, expected), mac)
+		require.NoError(t, c.VerifyHMAC(key, data, mac, ht))
+
+		streamed, err := c.HMACStream(key, bytes.NewReader(data), ht)
+		require.NoError(t, err)
+		require.Equal(t, mac, streamed)
+
+		mac[0] ^= 1
+		require.ErrorIs(t, c.VerifyHMAC(key, data, mac, ht), ErrHMACVerification)
+		require.ErrorIs(t, c.VerifyHMAC(key, data, mac[:4], ht), ErrHMACVerification)
+	}
+
+	mac, err := c.HMAC(key, data, DefaultHashType)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, vectors[Sha512]), mac)
+
+	_, err = c.HMAC(nil, data, Sha256)
+	require.ErrorIs(t, err, ErrUnsupportedParameter)
+	_, err = c.HMAC(key, data, HashType(100))
+	require.ErrorIs(t, err, ErrUnsupportedHashType)
+}
+
+func TestHMACWriter(t *testing.T) {
+	c := &Crypto{}
+	w, err := c.NewHMACWriter([]byte("key"), Sha256)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("hello, "))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("world"))
+	require.NoError(t, err)
+
+	mac, err := c.HMAC([]byte("key"), []byte("hello, world"), Sha256)
+	require.NoError(t, err)
+	require.Equal(t, mac, w.Sum())
+	require.NoError(t, w.Verify(mac))
+
+	w.Reset()
+	require.ErrorIs(t, w.Verify(mac), ErrHMACVerification)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+// maxMessageInfoLen limits the header size accepted by InspectMessage
+const maxMessageInfoLen = 16 << 20
+
+// MessageInfo describes an encrypted message as recorded in its header.
+type MessageInfo struct {
+	// RecipientIDs are the identifiers of the keys the message is encrypted for
+	RecipientIDs [][]byte
+	// PasswordRecipients is the number of passwords the message is encrypted with
+	PasswordRecipients int
+	// SignerIDs are the identifiers of the signers of a SignAndEncrypt message.
+	// SignThenEncrypt keeps signers after the ciphertext, so they are not listed.
+	SignerIDs [][]byte
+	// IsSigned is set for SignAndEncrypt and SignThenEncrypt messages
+	IsSigned bool
+	// Cipher is the data encryption algorithm, e.g. "AES-256-GCM"
+	Cipher string
+	// Padded is set when the plaintext was padded before encryption
+	Padded bool
+	// HeaderLen is the length of the header, the ciphertext starts right after it
+	HeaderLen int
+}
+
+// HasRecipient reports whether the message is encrypted for the key with the identifier.
+func (m *MessageInfo) HasRecipient(id []byte) bool {
+	for _, r := range m.RecipientIDs {
+		if bytes.Equal(r, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectMessage reads the header of a message produced by Crypto.Encrypt and
+// its signed variants without decrypting it. Only the header is consumed
+// from r.
+func InspectMessage(r io.Reader) (_ *MessageInfo, err error) {
+	var (
+		serializer *foundation.MessageInfoDerSerializer
+		info       *foundation.MessageInfo
+	)
+	defer deleteObjects(serializer, info)
+
+	serializer = foundation.NewMessageInfoDerSerializer()
+	serializer.SetupDefaults()
+
+	header, err := readMessageInfo(r, serializer)
+	if err != nil {
+		return nil, err
+	}
+	if info, err = serializer.Deserialize(header); err != nil {
+		return nil, err
+	}
+
+	res := &MessageInfo{HeaderLen: len(header), Padded: info.HasCipherPaddingAlgInfo()}
+	res.RecipientIDs = keyRecipientIDs(info)
+	res.PasswordRecipients = passwordRecipientCount(info)
+	if res.Cipher, err = dataCipherName(info); err != nil {
+		return nil, err
+	}
+
+	if info.HasCustomParams() {
+		params := info.CustomParams()
+		defer deleteObjects(params)
+		for i := 0; ; i++ {
+			signerID, err := params.FindData(signerParamKey(signerIDKey, i))
+			if err != nil {
+				break
+			}
+			res.SignerIDs = append(res.SignerIDs, signerID)
+		}
+	}
+	res.IsSigned = len(res.SignerIDs) > 0
+	if !res.IsSigned && info.HasFooterInfo() {
+		footer := info.FooterInfo()
+		defer deleteObjects(footer)
+		res.IsSigned = footer.HasSignedDataInfo()
+	}
+	return res, nil
+}
+
+// readMessageInfo reads the DER encoded message info, its length is taken from the prefix
+func readMessageInfo(r io.Reader, serializer *foundation.MessageInfoDerSerializer) ([]byte, error) {
+	prefix := make([]byte, foundation.MessageInfoDerSerializerPrefixLen)
+	n, err := io.ReadFull(r, prefix)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrInvalidMessageHeader
+		}
+		return nil, err
+	}
+	prefix = prefix[:n]
+
+	total := int(serializer.ReadPrefix(prefix))
+	if total == 0 || total > maxMessageInfoLen {
+		return nil, ErrInvalidMessageHeader
+	}
+	if total <= n {
+		return prefix[:total], nil
+	}
+
+	header := make([]byte, total)
+	copy(header, prefix)
+	if _, err = io.ReadFull(r, header[n:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrInvalidMessageHeader
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+func keyRecipientIDs(info *foundation.MessageInfo) [][]byte {
+	var (
+		ids   [][]byte
+		lists []*foundation.KeyRecipientInfoList
+	)
+	defer func() {
+		for _, l := range lists {
+			deleteObjects(l)
+		}
+	}()
+
+	l := info.KeyRecipientInfoList()
+	for {
+		lists = append(lists, l)
+		if !l.HasItem() {
+			break
+		}
+		item := l.Item()
+		ids = append(ids, item.RecipientId())
+		deleteObjects(item)
+
+		if !l.HasNext() {
+			break
+		}
+		l = l.Next()
+	}
+	return ids
+}
+
+func passwordRecipientCount(info *foundation.MessageInfo) int {
+	var (
+		count int
+		lists []*foundation.PasswordRecipientInfoList
+	)
+	defer func() {
+		for _, l := range lists {
+			deleteObjects(l)
+		}
+	}()
+
+	l := info.PasswordRecipientInfoList()
+	for {
+		lists = append(lists, l)
+		if !l.HasItem() {
+			break
+		}
+		count++
+		if !l.HasNext() {
+			break
+		}
+		l = l.Next()
+	}
+	return count
+}
+
+func dataCipherName(info *foundation.MessageInfo) (string, error) {
+	algInfo, err := info.DataEncryptionAlgInfo()
+	if err != nil {
+		return "", err
+	}
+	defer deleteObjects(algInfo)
+
+	switch id := algInfo.AlgId(); id {
+	case foundation.AlgIdAes256Gcm:
+		return "AES-256-GCM", nil
+	case foundation.AlgIdAes256Cbc:
+		return "AES-256-CBC", nil
+	default:
+		return "AlgId(" + strconv.Itoa(int(id)) + ")", nil
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+const (
+	// DerivedKeyMaterialLen is the length of the key material returned by DeriveKeyMaterial
+	DerivedKeyMaterialLen = 64
+)
+
+var keyDerivationSalt = []byte("VIRGIL-KEY-DERIVATION")
+
+// HKDF derives length bytes from secret as defined in RFC 5869 with the hash
+// type t. salt and info may be empty.
+func (c *Crypto) HKDF(t HashType, secret, salt, info []byte, length int) ([]byte, error) {
+	hf, ok := stdHashMap[t]
+	if !ok {
+		return nil, ErrUnsupportedHashType
+	}
+	if length <= 0 || length > 255*hf().Size() {
+		return nil, ErrUnsupportedParameter
+	}
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(hf, secret, salt, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// PBKDF2 derives length bytes from password as defined in RFC 8018 with HMAC
+// of the hash type t as the pseudorandom function.
+func (c *Crypto) PBKDF2(t HashType, password, salt []byte, iterations int, length int) ([]byte, error) {
+	hf, ok := stdHashMap[t]
+	if !ok {
+		return nil, ErrUnsupportedHashType
+	}
+	if len(password) == 0 {
+		return nil, ErrPasswordIsEmpty
+	}
+	if iterations <= 0 || length <= 0 {
+		return nil, ErrUnsupportedParameter
+	}
+	return pbkdf2.Key(password, salt, iterations, length, hf), nil
+}
+
+// DeriveKeyFromPassword derives length bytes from password with Argon2id or
+// scrypt and the cost given in params, e.g. DefaultPasswordKDFParams.
+func (c *Crypto) DeriveKeyFromPassword(password, salt []byte, params PasswordKDFParams, length int) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, ErrPasswordIsEmpty
+	}
+	if length <= 0 {
+		return nil, ErrUnsupportedParameter
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return params.deriveKey(password, salt, length)
+}
+
+// DeriveKeyMaterial derives the key material of a child key from the key
+// material of its parent. Every element of the path is one HKDF-SHA512 step,
+// so the child of a derived key material at "b" equals the derivation at
+// "a", "b" from the root, and the key material of any node can be handed out
+// without revealing its parent and siblings.
+func (c *Crypto) DeriveKeyMaterial(keyMaterial []byte, path ...string) ([]byte, error) {
+	l := uint(len(keyMaterial))
+	if l < foundation.KeyMaterialRngKeyMaterialLenMin || l > foundation.KeyMaterialRngKeyMaterialLenMax {
+		return nil, ErrInvalidSeedSize
+	}
+	km := append([]byte{}, keyMaterial...)
+	for _, p := range path {
+		next, err := c.HKDF(Sha512, km, keyDerivationSalt, []byte(p), DerivedKeyMaterialLen)
+		wipe(km)
+		if err != nil {
+			return nil, err
+		}
+		km = next
+	}
+	return km, nil
+}
+
+// DeriveKeypairFromKeyMaterial deterministically generates the key pair of the
+// type t at the path below keyMaterial, see DeriveKeyMaterial.
+func (c *Crypto) DeriveKeypairFromKeyMaterial(t KeyType, keyMaterial []byte, path ...string) (PrivateKey, error) {
+	km, err := c.DeriveKeyMaterial(keyMaterial, path...)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(km)
+
+	return c.GenerateKeypairFromKeyMaterialForType(t, km)
+}
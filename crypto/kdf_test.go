@@ -0,0 +1,88 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestHKDF(t *testing.T) {
+	c := &Crypto{}
+	// RFC 5869, test case 1
+	okm, err := c.HKDF(
+		Sha256,
+		mustHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b"),
+		mustHex(t, "000102030405060708090a0b0c"),
+		mustHex(t, "f0f1f2f3f4f5f6f7f8f9"),
+		42,
+	)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"), okm)
+
+	_, err = c.HKDF(Sha256, []byte("secret"), nil, nil, 255*32+1)
+	require.ErrorIs(t, err, ErrUnsupportedParameter)
+	_, err = c.HKDF(HashType(100), []byte("secret"), nil, nil, 32)
+	require.ErrorIs(t, err, ErrUnsupportedHashType)
+}
+
+func TestPBKDF2(t *testing.T) {
+	c := &Crypto{}
+	dk, err := c.PBKDF2(Sha256, []byte("password"), []byte("salt"), 1, 32)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"), dk)
+
+	_, err = c.PBKDF2(Sha256, []byte("password"), []byte("salt"), 0, 32)
+	require.ErrorIs(t, err, ErrUnsupportedParameter)
+	_, err = c.PBKDF2(Sha256, nil, []byte("salt"), 1, 32)
+	require.ErrorIs(t, err, ErrPasswordIsEmpty)
+}
+
+func TestDeriveKeyFromPassword(t *testing.T) {
+	c := &Crypto{}
+	params := PasswordKDFParams{KDF: PasswordKDFArgon2id, Time: 1, Memory: 64, Threads: 1}
+	salt := []byte("somesaltsomesalt")
+	key, err := c.DeriveKeyFromPassword([]byte("password"), salt, params, 32)
+	require.NoError(t, err)
+	require.Equal(t, argon2.IDKey([]byte("password"), salt, 1, 64, 1, 32), key)
+
+	_, err = c.DeriveKeyFromPassword([]byte("password"), salt, PasswordKDFParams{KDF: PasswordKDFArgon2id}, 32)
+	require.ErrorIs(t, err, ErrUnsupportedParameter)
+	_, err = c.DeriveKeyFromPassword([]byte("password"), salt, PasswordKDFParams{}, 32)
+	require.ErrorIs(t, err, ErrUnsupportedPasswordKDF)
+}
+
+func TestDeriveKeyMaterial(t *testing.T) {
+	c := &Crypto{}
+	root := bytes.Repeat([]byte{1}, 32)
+
+	same, err := c.DeriveKeyMaterial(root)
+	require.NoError(t, err)
+	require.Equal(t, root, same)
+
+	ab, err := c.DeriveKeyMaterial(root, "a", "b")
+	require.NoError(t, err)
+	require.Len(t, ab, DerivedKeyMaterialLen)
+
+	a, err := c.DeriveKeyMaterial(root, "a")
+	require.NoError(t, err)
+	b, err := c.DeriveKeyMaterial(a, "b")
+	require.NoError(t, err)
+	require.Equal(t, ab, b)
+
+	ba, err := c.DeriveKeyMaterial(root, "b", "a")
+	require.NoError(t, err)
+	require.NotEqual(t, ab, ba)
+	require.Equal(t, bytes.Repeat([]byte{1}, 32), root)
+
+	_, err = c.DeriveKeyMaterial(root[:31], "a")
+	require.ErrorIs(t, err, ErrInvalidSeedSize)
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+// HybridKEMSharedKeyLen is the length of the shared key of a hybrid KEM key
+const HybridKEMSharedKeyLen = 32
+
+// Hybrid encapsulated key layout:
+//
+//	version(1) | first encapsulated key length(2) | first encapsulated key | second encapsulated key
+//
+// The first and second encapsulated keys are produced by the classical and the
+// post-quantum component of the key. The shared key is
+//
+//	HKDF-SHA512(secret = first secret | second secret, salt = none,
+//	            info = "VIRGIL-HYBRID-KEM" | hybrid encapsulated key, length = 32)
+const (
+	hybridKEMVersion   = 1
+	hybridKEMHeaderLen = 1 + 2
+)
+
+var hybridKEMLabel = []byte("VIRGIL-HYBRID-KEM")
+
+// Encapsulate generates a shared key for the public key and returns it with
+// its encapsulation, which the holder of the private key turns back into the
+// shared key with Decapsulate. Curve25519, P-256, ML-KEM and hybrid KEM keys
+// are supported.
+//
+// For hybrid keys both components encapsulate a secret; the shared key is
+// derived from the two secrets and the encapsulated key with HKDF-SHA512, so
+// it stays secret as long as one of the algorithms is not broken.
+func (c *Crypto) Encapsulate(pub PublicKey) (encapsulatedKey []byte, sharedKey []byte, err error) {
+	if !isKEMKeyType(pub.KeyType()) {
+		return nil, nil, ErrUnsupportedKeyType
+	}
+	if hybrid, ok := pub.Unwrap().(*foundation.HybridPublicKey); ok {
+		return c.hybridEncapsulate(hybrid)
+	}
+	return kemEncapsulate(pub.Unwrap())
+}
+
+// Decapsulate returns the shared key of an encapsulated key produced by
+// Encapsulate for the public key of priv.
+func (c *Crypto) Decapsulate(encapsulatedKey []byte, priv PrivateKey) ([]byte, error) {
+	if !isKEMKeyType(priv.KeyType()) {
+		return nil, ErrUnsupportedKeyType
+	}
+	if hybrid, ok := priv.Unwrap().(*foundation.HybridPrivateKey); ok {
+		return c.hybridDecapsulate(encapsulatedKey, hybrid)
+	}
+	return kemDecapsulate(encapsulatedKey, priv.Unwrap())
+}
+
+func isKEMKeyType(kt KeyType) bool {
+	switch kt {
+	case Curve25519, P256r1, MlKem768, MlKem1024:
+		return true
+	}
+	return kt.cipher != AlgNone && kt.pqCipher != AlgNone && kt.signer == AlgNone
+}
+
+func kemAlg(key foundation.Key) (foundation.Kem, error) {
+	alg, err := foundation.KeyAlgFactoryCreateFromKey(key, random)
+	if err != nil {
+		return nil, err
+	}
+	kem, ok := alg.(foundation.Kem)
+	if !ok {
+		deleteObjects(alg)
+		return nil, ErrUnsupportedKeyType
+	}
+	return kem, nil
+}
+
+func kemEncapsulate(pub foundation.PublicKey) (encapsulatedKey []byte, sharedKey []byte, err error) {
+	kem, err := kemAlg(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer deleteObjects(kem)
+
+	sharedKey, encapsulatedKey, err = kem.KemEncapsulate(pub)
+	return encapsulatedKey, sharedKey, err
+}
+
+func kemDecapsulate(encapsulatedKey []byte, priv foundation.PrivateKey) ([]byte, error) {
+	kem, err := kemAlg(priv)
+	if err != nil {
+		return nil, err
+	}
+	defer deleteObjects(kem)
+
+	return kem.KemDecapsulate(encapsulatedKey, priv)
+}
+
+func (c *Crypto) hybridEncapsulate(pub *foundation.HybridPublicKey) ([]byte, []byte, error) {
+	first, firstSecret, err := kemEncapsulate(pub.FirstKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wipe(firstSecret)
+	second, secondSecret, err := kemEncapsulate(pub.SecondKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wipe(secondSecret)
+
+	encapsulatedKey, err := joinHybridEncapsulatedKey(first, second)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedKey, err := c.combineHybridSecrets(firstSecret, secondSecret, encapsulatedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encapsulatedKey, sharedKey, nil
+}
+
+func (c *Crypto) hybridDecapsulate(encapsulatedKey []byte, priv *foundation.HybridPrivateKey) ([]byte, error) {
+	first, second, err := splitHybridEncapsulatedKey(encapsulatedKey)
+	if err != nil {
+		return nil, err
+	}
+	firstSecret, err := kemDecapsulate(first, priv.FirstKey())
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(firstSecret)
+	secondSecret, err := kemDecapsulate(second, priv.SecondKey())
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(secondSecret)
+
+	return c.combineHybridSecrets(firstSecret, secondSecret, encapsulatedKey)
+}
+
+func joinHybridEncapsulatedKey(first, second []byte) ([]byte, error) {
+	if len(first) > math.MaxUint16 {
+		return nil, ErrUnsupportedKeyType
+	}
+	encapsulatedKey := make([]byte, hybridKEMHeaderLen, hybridKEMHeaderLen+len(first)+len(second))
+	encapsulatedKey[0] = hybridKEMVersion
+	binary.BigEndian.PutUint16(encapsulatedKey[1:], uint16(len(first)))
+	return append(append(encapsulatedKey, first...), second...), nil
+}
+
+func splitHybridEncapsulatedKey(encapsulatedKey []byte) (first, second []byte, err error) {
+	if len(encapsulatedKey) < hybridKEMHeaderLen {
+		return nil, nil, ErrInvalidEncapsulatedKey
+	}
+	if encapsulatedKey[0] != hybridKEMVersion {
+		return nil, nil, ErrUnsupportedEncapsulatedKeyVersion
+	}
+	firstLen := int(binary.BigEndian.Uint16(encapsulatedKey[1:]))
+	rest := encapsulatedKey[hybridKEMHeaderLen:]
+	if firstLen == 0 || firstLen >= len(rest) {
+		return nil, nil, ErrInvalidEncapsulatedKey
+	}
+	return rest[:firstLen], rest[firstLen:], nil
+}
+
+func (c *Crypto) combineHybridSecrets(firstSecret, secondSecret, encapsulatedKey []byte) ([]byte, error) {
+	secret := make([]byte, 0, len(firstSecret)+len(secondSecret))
+	secret = append(append(secret, firstSecret...), secondSecret...)
+	defer wipe(secret)
+
+	info := make([]byte, 0, len(hybridKEMLabel)+len(encapsulatedKey))
+	info = append(append(info, hybridKEMLabel...), encapsulatedKey...)
+	return c.HKDF(Sha512, secret, nil, info, HybridKEMSharedKeyLen)
+}
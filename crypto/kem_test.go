@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKEMKeyType(t *testing.T) {
+	for _, kt := range []KeyType{
+		Curve25519, P256r1, MlKem768, MlKem1024,
+		HybridKEM(AlgCurve25519, AlgMlKem768), HybridKEM(AlgCurve25519, AlgMlKem1024),
+	} {
+		require.True(t, isKEMKeyType(kt), kt.String())
+	}
+	for _, kt := range []KeyType{Ed25519, RsaKey(2048), Curve25519Ed25519, Curve25519MlKem1024Ed25519MlDsa87} {
+		require.False(t, isKEMKeyType(kt), kt.String())
+	}
+}
+
+func TestHybridEncapsulatedKey(t *testing.T) {
+	encapsulatedKey, err := joinHybridEncapsulatedKey([]byte{1, 2}, []byte{3, 4, 5})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0, 2, 1, 2, 3, 4, 5}, encapsulatedKey)
+
+	first, second, err := splitHybridEncapsulatedKey(encapsulatedKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, first)
+	require.Equal(t, []byte{3, 4, 5}, second)
+
+	for _, bad := range [][]byte{nil, {1, 0}, {1, 0, 0, 1}, {1, 0, 2, 1, 2}, {1, 0xff, 0xff, 1}} {
+		_, _, err = splitHybridEncapsulatedKey(bad)
+		require.ErrorIs(t, err, ErrInvalidEncapsulatedKey)
+	}
+	_, _, err = splitHybridEncapsulatedKey([]byte{2, 0, 2, 1, 2, 3, 4, 5})
+	require.ErrorIs(t, err, ErrUnsupportedEncapsulatedKeyVersion)
+}
+
+// the expected key is HKDF-SHA512 computed independently of this package
+func TestCombineHybridSecrets_Vector(t *testing.T) {
+	c := &Crypto{}
+	encapsulatedKey, err := joinHybridEncapsulatedKey(bytes.Repeat([]byte{0xaa}, 32), bytes.Repeat([]byte{0xbb}, 48))
+	require.NoError(t, err)
+	key, err := c.combineHybridSecrets(bytes.Repeat([]byte{0x01}, 32), bytes.Repeat([]byte{0x02}, 32), encapsulatedKey)
+	require.NoError(t, err)
+	require.Equal(t, "6f6a53790645e667f6e6f12f409e42899a8a597c5db9f7e4adef9f6e441ab9b1", hex.EncodeToString(key))
+}
+
+func TestCombineHybridSecrets(t *testing.T) {
+	c := &Crypto{}
+	key, err := c.combineHybridSecrets([]byte("first"), []byte("second"), []byte("encapsulated"))
+	require.NoError(t, err)
+	require.Len(t, key, HybridKEMSharedKeyLen)
+
+	same, err := c.combineHybridSecrets([]byte("first"), []byte("second"), []byte("encapsulated"))
+	require.NoError(t, err)
+	require.Equal(t, key, same)
+
+	// the shared key depends on both secrets and on the encapsulated key
+	for _, other := range [][3]string{
+		{"first", "other", "encapsulated"},
+		{"other", "second", "encapsulated"},
+		{"first", "second", "other"},
+	} {
+		k, err := c.combineHybridSecrets([]byte(other[0]), []byte(other[1]), []byte(other[2]))
+		require.NoError(t, err)
+		require.NotEqual(t, key, k)
+	}
+}
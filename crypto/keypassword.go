@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordKDF is the key derivation function that turns a password into
+// the key protecting an exported private key.
+type PasswordKDF byte
+
+const (
+	PasswordKDFArgon2id PasswordKDF = 1
+	PasswordKDFScrypt   PasswordKDF = 2
+)
+
+// PasswordKDFParams are the cost parameters of a password KDF. Time, Memory
+// (in KiB) and Threads are used by Argon2id, N, R and P by scrypt.
+type PasswordKDFParams struct {
+	KDF PasswordKDF
+
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+
+	N uint32
+	R uint32
+	P uint32
+}
+
+var (
+	// DefaultPasswordKDFParams is Argon2id with the second recommended option of RFC 9106
+	DefaultPasswordKDFParams = PasswordKDFParams{KDF: PasswordKDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+	// ScryptPasswordKDFParams is scrypt with the cost recommended for interactive logins
+	ScryptPasswordKDFParams = PasswordKDFParams{KDF: PasswordKDFScrypt, N: 1 << 15, R: 8, P: 1}
+)
+
+// Envelope layout, every field is authenticated as associated data:
+//
+//	magic(4) | version(1) | kdf(1) | param1(4) | param2(4) | param3(4) | salt(16) | nonce(12) | AES-256-GCM ciphertext
+const (
+	keyEnvelopeVersion   = 1
+	keyEnvelopeSaltLen   = 16
+	keyEnvelopeNonceLen  = 12
+	keyEnvelopeKeyLen    = 32
+	keyEnvelopeHeaderLen = 4 + 1 + 1 + 3*4 + keyEnvelopeSaltLen + keyEnvelopeNonceLen
+
+	// limits of the parameters accepted on import, so that a crafted envelope
+	// cannot make the KDF allocate more than maxPasswordKDFMemory bytes
+	// (Argon2id uses Memory KiB, scrypt 128*N*R bytes) or run for too long
+	maxPasswordKDFMemory = 1 << 30
+	maxArgon2Time        = 64
+	maxScryptRP          = 1 << 10
+)
+
+var keyEnvelopeMagic = []byte("VKPW")
+
+// ExportPrivateKeyWithPassword exports the private key encrypted with a key
+// derived from password by DefaultPasswordKDFParams.
+func (c *Crypto) ExportPrivateKeyWithPassword(key PrivateKey, password []byte) ([]byte, error) {
+	return c.ExportPrivateKeyWithPasswordParams(key, password, DefaultPasswordKDFParams)
+}
+
+func (c *Crypto) ExportPrivateKeyWithPasswordParams(key PrivateKey, password []byte, params PasswordKDFParams) ([]byte, error) {
+	exported, err := c.ExportPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(exported)
+
+	return sealWithPassword(exported, password, params)
+}
+
+// ImportPrivateKeyWithPassword imports a private key exported by
+// ExportPrivateKeyWithPassword. The KDF parameters are read from the envelope.
+func (c *Crypto) ImportPrivateKeyWithPassword(data []byte, password []byte) (PrivateKey, error) {
+	exported, err := openWithPassword(data, password)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(exported)
+
+	return c.ImportPrivateKey(exported)
+}
+
+func sealWithPassword(plaintext, password []byte, params PasswordKDFParams) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, ErrPasswordIsEmpty
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, keyEnvelopeHeaderLen)
+	copy(header, keyEnvelopeMagic)
+	header[4] = keyEnvelopeVersion
+	header[5] = byte(params.KDF)
+	p1, p2, p3 := params.encode()
+	binary.BigEndian.PutUint32(header[6:], p1)
+	binary.BigEndian.PutUint32(header[10:], p2)
+	binary.BigEndian.PutUint32(header[14:], p3)
+	saltNonce := header[18:]
+	if _, err := io.ReadFull(rand.Reader, saltNonce); err != nil {
+		return nil, err
+	}
+	salt, nonce := saltNonce[:keyEnvelopeSaltLen], saltNonce[keyEnvelopeSaltLen:]
+
+	aead, err := params.aead(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+func openWithPassword(data, password []byte) ([]byte, error) {
+	if len(data) < keyEnvelopeHeaderLen || !bytes.Equal(data[:4], keyEnvelopeMagic) {
+		return nil, ErrInvalidKeyEnvelope
+	}
+	if data[4] != keyEnvelopeVersion {
+		return nil, ErrUnsupportedKeyEnvelopeVersion
+	}
+	params := decodePasswordKDFParams(PasswordKDF(data[5]),
+		binary.BigEndian.Uint32(data[6:]),
+		binary.BigEndian.Uint32(data[10:]),
+		binary.BigEndian.Uint32(data[14:]),
+	)
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	header := data[:keyEnvelopeHeaderLen]
+	salt := header[18 : 18+keyEnvelopeSaltLen]
+	nonce := header[18+keyEnvelopeSaltLen:]
+
+	aead, err := params.aead(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, data[keyEnvelopeHeaderLen:], header)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	return plaintext, nil
+}
+
+func (p PasswordKDFParams) validate() error {
+	switch p.KDF {
+	case PasswordKDFArgon2id:
+		if p.Time == 0 || p.Time > maxArgon2Time || p.Threads == 0 ||
+			uint64(p.Memory) < 8*uint64(p.Threads) || uint64(p.Memory)*1024 > maxPasswordKDFMemory {
+			return ErrUnsupportedParameter
+		}
+	case PasswordKDFScrypt:
+		if p.N <= 1 || p.N&(p.N-1) != 0 || p.R == 0 || p.P == 0 ||
+			uint64(p.R)*uint64(p.P) > maxScryptRP || 128*uint64(p.N)*uint64(p.R) > maxPasswordKDFMemory {
+			return ErrUnsupportedParameter
+		}
+	default:
+		return ErrUnsupportedPasswordKDF
+	}
+	return nil
+}
+
+func (p PasswordKDFParams) encode() (uint32, uint32, uint32) {
+	if p.KDF == PasswordKDFScrypt {
+		return p.N, p.R, p.P
+	}
+	return p.Time, p.Memory, uint32(p.Threads)
+}
+
+func decodePasswordKDFParams(kdf PasswordKDF, p1, p2, p3 uint32) PasswordKDFParams {
+	if kdf == PasswordKDFScrypt {
+		return PasswordKDFParams{KDF: kdf, N: p1, R: p2, P: p3}
+	}
+	if p3 > 255 {
+		p3 = 0 // rejected by validate
+	}
+	return PasswordKDFParams{KDF: kdf, Time: p1, Memory: p2, Threads: uint8(p3)}
+}
+
+func (p PasswordKDFParams) aead(password, salt []byte) (cipher.AEAD, error) {
+	key, err := p.deriveKey(password, salt, keyEnvelopeKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+
+	return NewAEAD(SymmetricAES256GCM, key)
+}
+
+func (p PasswordKDFParams) deriveKey(password, salt []byte, length int) ([]byte, error) {
+	switch p.KDF {
+	case PasswordKDFArgon2id:
+		return argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, uint32(length)), nil
+	case PasswordKDFScrypt:
+		return scrypt.Key(password, salt, int(p.N), int(p.R), int(p.P), length)
+	default:
+		return nil, ErrUnsupportedPasswordKDF
+	}
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,94 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testPasswordKDFParams = []PasswordKDFParams{
+	{KDF: PasswordKDFArgon2id, Time: 1, Memory: 64, Threads: 1},
+	{KDF: PasswordKDFScrypt, N: 16, R: 1, P: 1},
+}
+
+func TestPasswordEnvelope(t *testing.T) {
+	secret := []byte("exported private key")
+	for _, params := range testPasswordKDFParams {
+		sealed, err := sealWithPassword(secret, []byte("password"), params)
+		require.NoError(t, err)
+
+		opened, err := openWithPassword(sealed, []byte("password"))
+		require.NoError(t, err)
+		require.Equal(t, secret, opened)
+
+		_, err = openWithPassword(sealed, []byte("wrong"))
+		require.Equal(t, ErrInvalidPassword, err)
+
+		// the header is authenticated
+		tampered := append([]byte(nil), sealed...)
+		tampered[keyEnvelopeHeaderLen-1] ^= 1
+		_, err = openWithPassword(tampered, []byte("password"))
+		require.Equal(t, ErrInvalidPassword, err)
+
+		tampered = append([]byte(nil), sealed...)
+		tampered[4] = keyEnvelopeVersion + 1
+		_, err = openWithPassword(tampered, []byte("password"))
+		require.Equal(t, ErrUnsupportedKeyEnvelopeVersion, err)
+	}
+}
+
+func TestPasswordEnvelope_BadInput(t *testing.T) {
+	_, err := sealWithPassword([]byte("key"), nil, DefaultPasswordKDFParams)
+	require.Equal(t, ErrPasswordIsEmpty, err)
+
+	_, err = sealWithPassword([]byte("key"), []byte("password"), PasswordKDFParams{KDF: 42})
+	require.Equal(t, ErrUnsupportedPasswordKDF, err)
+
+	_, err = sealWithPassword([]byte("key"), []byte("password"), PasswordKDFParams{KDF: PasswordKDFScrypt, N: 3, R: 1, P: 1})
+	require.Equal(t, ErrUnsupportedParameter, err)
+
+	_, err = openWithPassword([]byte("VKPW"), []byte("password"))
+	require.Equal(t, ErrInvalidKeyEnvelope, err)
+
+	// a crafted envelope must not make the KDF allocate unbounded memory
+	sealed, err := sealWithPassword([]byte("key"), []byte("password"), testPasswordKDFParams[0])
+	require.NoError(t, err)
+	sealed[10], sealed[11] = 0xff, 0xff
+	_, err = openWithPassword(sealed, []byte("password"))
+	require.Equal(t, ErrUnsupportedParameter, err)
+}
+
+func TestPasswordEnvelope_OversizedParams(t *testing.T) {
+	oversized := []PasswordKDFParams{
+		// 4 GiB of Argon2id memory
+		{KDF: PasswordKDFArgon2id, Time: 1, Memory: 4 * 1024 * 1024, Threads: 1},
+		// 128*N*R = 512 GiB of scrypt memory
+		{KDF: PasswordKDFScrypt, N: 1 << 22, R: 1024, P: 1},
+		// 128*N*R = 2 GiB
+		{KDF: PasswordKDFScrypt, N: 1 << 21, R: 8, P: 1},
+		// R*P wraps around as uint32
+		{KDF: PasswordKDFScrypt, N: 16, R: 1 << 16, P: 1 << 16},
+	}
+	for _, params := range oversized {
+		require.Equal(t, ErrUnsupportedParameter, params.validate(), params)
+
+		// the parameters are checked before the KDF runs, otherwise opening
+		// the envelope would allocate gigabytes of memory
+		sealed, err := sealWithPassword([]byte("key"), []byte("password"), testPasswordKDFParams[0])
+		require.NoError(t, err)
+		sealed[5] = byte(params.KDF)
+		p1, p2, p3 := params.encode()
+		binary.BigEndian.PutUint32(sealed[6:], p1)
+		binary.BigEndian.PutUint32(sealed[10:], p2)
+		binary.BigEndian.PutUint32(sealed[14:], p3)
+		_, err = openWithPassword(sealed, []byte("password"))
+		require.Equal(t, ErrUnsupportedParameter, err)
+	}
+
+	// the largest accepted costs stay within the limit
+	require.NoError(t, PasswordKDFParams{KDF: PasswordKDFArgon2id, Time: 1, Memory: 1024 * 1024, Threads: 4}.validate())
+	require.NoError(t, PasswordKDFParams{KDF: PasswordKDFScrypt, N: 1 << 20, R: 8, P: 1}.validate())
+	require.NoError(t, DefaultPasswordKDFParams.validate())
+	require.NoError(t, ScryptPasswordKDFParams.validate())
+}
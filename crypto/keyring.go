@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+// PrivateKeyFinder looks up a private key by its identifier. It returns
+// ErrKeyNotFound when there is no such key.
+type PrivateKeyFinder interface {
+	FindPrivateKey(id []byte) (PrivateKey, error)
+}
+
+var _ PrivateKeyFinder = &Keyring{}
+
+// Keyring is an in-memory set of private keys indexed by Identifier.
+// It is safe for concurrent use.
+type Keyring struct {
+	mu   sync.RWMutex
+	keys map[string]PrivateKey
+}
+
+func NewKeyring(keys ...PrivateKey) *Keyring {
+	k := &Keyring{keys: make(map[string]PrivateKey, len(keys))}
+	k.Add(keys...)
+	return k
+}
+
+// Add adds keys to the keyring, replacing keys with the same identifier.
+func (k *Keyring) Add(keys ...PrivateKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, key := range keys {
+		k.keys[string(key.Identifier())] = key
+	}
+}
+
+func (k *Keyring) Remove(id []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, string(id))
+}
+
+func (k *Keyring) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.keys)
+}
+
+func (k *Keyring) FindPrivateKey(id []byte) (PrivateKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[string(id)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// FindMessageKey returns the first key of keyring the message is encrypted for.
+func FindMessageKey(info *MessageInfo, keyring PrivateKeyFinder) (PrivateKey, error) {
+	for _, id := range info.RecipientIDs {
+		key, err := keyring.FindPrivateKey(id)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// DecryptWithKeyring decrypts data with the key of keyring listed among the
+// message recipients.
+func (c *Crypto) DecryptWithKeyring(data []byte, keyring PrivateKeyFinder) ([]byte, error) {
+	info, err := InspectMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	key, err := FindMessageKey(info, keyring)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decrypt(data, key)
+}
+
+// DecryptStreamWithKeyring decrypts in with the key of keyring listed among
+// the message recipients. The header is read once and replayed to the cipher.
+func (c *Crypto) DecryptStreamWithKeyring(in io.Reader, out io.Writer, keyring PrivateKeyFinder) error {
+	header := bytes.NewBuffer(nil)
+	info, err := InspectMessage(io.TeeReader(in, header))
+	if err != nil {
+		return err
+	}
+	key, err := FindMessageKey(info, keyring)
+	if err != nil {
+		return err
+	}
+	return c.DecryptStream(io.MultiReader(header, in), out, key)
+}
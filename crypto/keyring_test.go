@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPrivateKey struct {
+	PrivateKey
+	id []byte
+}
+
+func (k testPrivateKey) Identifier() []byte {
+	return k.id
+}
+
+type failingFinder struct{}
+
+func (failingFinder) FindPrivateKey([]byte) (PrivateKey, error) {
+	return nil, errors.New("storage is unavailable")
+}
+
+func TestKeyring(t *testing.T) {
+	alice := testPrivateKey{id: []byte("alice")}
+	bob := testPrivateKey{id: []byte("bob")}
+
+	k := NewKeyring(alice)
+	k.Add(bob)
+	require.Equal(t, 2, k.Len())
+
+	key, err := k.FindPrivateKey([]byte("bob"))
+	require.NoError(t, err)
+	require.Equal(t, bob, key)
+
+	k.Remove([]byte("bob"))
+	k.Remove([]byte("unknown"))
+	require.Equal(t, 1, k.Len())
+	_, err = k.FindPrivateKey([]byte("bob"))
+	require.Equal(t, ErrKeyNotFound, err)
+
+	info := &MessageInfo{RecipientIDs: [][]byte{[]byte("carol"), []byte("alice")}}
+	key, err = FindMessageKey(info, k)
+	require.NoError(t, err)
+	require.Equal(t, alice, key)
+
+	_, err = FindMessageKey(&MessageInfo{RecipientIDs: [][]byte{[]byte("carol")}}, k)
+	require.Equal(t, ErrKeyNotFound, err)
+
+	_, err = FindMessageKey(info, failingFinder{})
+	require.EqualError(t, err, "storage is unavailable")
+}
@@ -37,6 +37,8 @@
 package crypto
 
 import (
+	"strconv"
+
 	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
 )
 
@@ -62,10 +64,19 @@ var (
 	Curve25519 = KeyType{simple: AlgCurve25519}
 	Ed25519    = KeyType{simple: AlgEd25519}
 
+	// Post-quantum
+	MlKem768  = KeyType{simple: AlgMlKem768}
+	MlKem1024 = KeyType{simple: AlgMlKem1024}
+
 	// Recommended compound types
 	Curve25519Ed25519                = CompoundKey(AlgCurve25519, AlgNone, AlgEd25519, AlgNone)
 	Curve25519MlKem768Ed25519Falcon  = CompoundKey(AlgCurve25519, AlgMlKem768, AlgEd25519, AlgFalcon)
 	Curve25519MlKem768Ed25519MlDsa65 = CompoundKey(AlgCurve25519, AlgMlKem768, AlgEd25519, AlgMlDsa65)
+
+	// Compound types for NIST security category 5, the second one with
+	// hash-based signatures that rely only on the security of SHA-2
+	Curve25519MlKem1024Ed25519MlDsa87         = CompoundKey(AlgCurve25519, AlgMlKem1024, AlgEd25519, AlgMlDsa87)
+	Curve25519MlKem1024Ed25519SlhDsaSha2_256s = CompoundKey(AlgCurve25519, AlgMlKem1024, AlgEd25519, AlgSlhDsaSha2_256s)
 )
 
 // RsaKey returns a KeyType for an RSA keypair with the given bit length.
@@ -89,6 +100,30 @@ func CompoundKey(cipher, pqCipher, signer, pqSigner Algorithm) KeyType {
 	return KeyType{cipher: cipher, pqCipher: pqCipher, signer: signer, pqSigner: pqSigner}
 }
 
+// String describes the key type, e.g. "Ed25519", "RSA-2048",
+// "Hybrid(Curve25519+ML-KEM-768)" or "Compound(Curve25519;Ed25519+Falcon)".
+func (kt KeyType) String() string {
+	pair := func(classical, pq Algorithm) string {
+		if pq == AlgNone {
+			return classical.String()
+		}
+		return classical.String() + "+" + pq.String()
+	}
+
+	switch {
+	case kt.rsaBitlen > 0:
+		return "RSA-" + strconv.FormatUint(uint64(kt.rsaBitlen), 10)
+	case kt.simple != AlgNone:
+		return kt.simple.String()
+	case kt.cipher != AlgNone && kt.signer != AlgNone:
+		return "Compound(" + pair(kt.cipher, kt.pqCipher) + ";" + pair(kt.signer, kt.pqSigner) + ")"
+	case kt.cipher != AlgNone && kt.pqCipher != AlgNone:
+		return "Hybrid(" + pair(kt.cipher, kt.pqCipher) + ")"
+	default:
+		return "Unknown"
+	}
+}
+
 func (kt KeyType) generatePrivateKey(kp *foundation.KeyProvider) (foundation.PrivateKey, error) {
 	switch {
 	case kt.rsaBitlen > 0:
@@ -15,6 +15,12 @@ func TestGetKeyType(t *testing.T) {
 		Curve25519MlKem768Ed25519Falcon,
 		HybridKEM(AlgCurve25519, AlgMlKem768),
 		CompoundKey(AlgCurve25519, AlgNone, AlgEd25519, AlgMlDsa65),
+		MlKem768, MlKem1024,
+		Curve25519MlKem1024Ed25519MlDsa87,
+		Curve25519MlKem1024Ed25519SlhDsaSha2_256s,
+		CompoundKey(AlgCurve25519, AlgMlKem768, AlgEd25519, AlgMlDsa44),
+		CompoundKey(AlgCurve25519, AlgNone, AlgEd25519, AlgSlhDsaSha2_128s),
+		HybridKEM(AlgCurve25519, AlgMlKem1024),
 	}
 	for _, kt := range types {
 		sk, err := c.GenerateKeypairForType(kt)
@@ -23,3 +29,36 @@ func TestGetKeyType(t *testing.T) {
 		require.Equal(t, kt, sk.PublicKey().KeyType())
 	}
 }
+
+func TestKeyTypeString(t *testing.T) {
+	require.Equal(t, "Ed25519", Ed25519.String())
+	require.Equal(t, "P-256", P256r1.String())
+	require.Equal(t, "RSA-4096", RsaKey(4096).String())
+	require.Equal(t, "Hybrid(Curve25519+ML-KEM-768)", HybridKEM(AlgCurve25519, AlgMlKem768).String())
+	require.Equal(t, "Compound(Curve25519;Ed25519)", Curve25519Ed25519.String())
+	require.Equal(t, "Compound(Curve25519+ML-KEM-768;Ed25519+Falcon)", Curve25519MlKem768Ed25519Falcon.String())
+	require.Equal(t, "ML-KEM-1024", MlKem1024.String())
+	require.Equal(t, "Compound(Curve25519+ML-KEM-1024;Ed25519+ML-DSA-87)", Curve25519MlKem1024Ed25519MlDsa87.String())
+	require.Equal(t,
+		"Compound(Curve25519+ML-KEM-1024;Ed25519+SLH-DSA-SHA2-256s)",
+		Curve25519MlKem1024Ed25519SlhDsaSha2_256s.String(),
+	)
+}
+
+func TestAlgorithmMapping(t *testing.T) {
+	algs := []Algorithm{
+		AlgEd25519, AlgCurve25519, AlgP256r1, AlgFalcon, AlgMlKem768, AlgMlDsa65,
+		AlgMlKem1024, AlgMlDsa44, AlgMlDsa87, AlgSlhDsaSha2_128s, AlgSlhDsaSha2_256s,
+	}
+	names := map[string]bool{}
+	for _, a := range algs {
+		require.NotEqual(t, "None", a.String())
+		require.False(t, names[a.String()], a.String())
+		names[a.String()] = true
+
+		require.Equal(t, a, algFromFoundation(algToFoundation(a)), a.String())
+		require.Equal(t, a, algorithmByDetachedID(detachedAlgorithmIDs[a]), a.String())
+	}
+	require.Equal(t, "None", (AlgSlhDsaSha2_256s + 1).String())
+	require.Equal(t, AlgNone, algFromFoundation(algToFoundation(AlgNone)))
+}
@@ -0,0 +1,39 @@
+//go:build go1.24
+
+package crypto_test
+
+import (
+	"crypto/mlkem"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+)
+
+// TestMlKem1024Interop checks ML-KEM-1024 against the independent
+// implementation of the standard library.
+func TestMlKem1024Interop(t *testing.T) {
+	vcrypto := &crypto.Crypto{}
+	key, err := vcrypto.GenerateKeypairForType(crypto.MlKem1024)
+	require.NoError(t, err)
+
+	exported, err := vcrypto.ExportPublicKey(key.PublicKey())
+	require.NoError(t, err)
+	var spki struct {
+		Algorithm asn1.RawValue
+		PublicKey asn1.BitString
+	}
+	rest, err := asn1.Unmarshal(exported, &spki)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+
+	ek, err := mlkem.NewEncapsulationKey1024(spki.PublicKey.Bytes)
+	require.NoError(t, err)
+	sharedKey, ciphertext := ek.Encapsulate()
+
+	decapsulated, err := vcrypto.Decapsulate(ciphertext, key)
+	require.NoError(t, err)
+	require.Equal(t, sharedKey, decapsulated)
+}
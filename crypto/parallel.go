@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// EncryptChunkedStreamParallel is the parallel version of EncryptChunkedStream
+// and produces the same chunked message format, sealing up to workers chunks
+// concurrently, runtime.GOMAXPROCS(0) if workers is not positive. Chunks are
+// written to out in order while the following ones are being read and
+// sealed, so at most about 2*workers chunks are kept in memory.
+//
+// The output is NOT compatible with EncryptStream and Decrypt/DecryptStream:
+// it must be decrypted with NewChunkedDecrypter. EncryptStream has no
+// parallel mode because its message format is sealed sequentially, so
+// callers that want parallel encryption have to switch both sides to the
+// chunked format.
+func (c *Crypto) EncryptChunkedStreamParallel(in io.Reader, out io.Writer, workers int, recipients ...PublicKey) error {
+	key, err := c.GenerateSymmetricKey()
+	if err != nil {
+		return err
+	}
+	defer wipe(key)
+
+	wrappedKey, err := c.Encrypt(key, recipients...)
+	if err != nil {
+		return err
+	}
+	header, s, err := newChunkedStream(SymmetricAES256GCM, key, wrappedKey, DefaultChunkLen)
+	if err != nil {
+		return err
+	}
+	if _, err = out.Write(header); err != nil {
+		return err
+	}
+	return sealChunksParallel(in, out, s, DefaultChunkLen, workers)
+}
+
+type sealJob struct {
+	index uint32
+	last  bool
+	buf   []byte
+	done  chan struct{}
+}
+
+func sealChunksParallel(in io.Reader, out io.Writer, s *symmetricStream, chunkLen int, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jobs := make(chan *sealJob)
+	ordered := make(chan *sealJob, workers)
+	stop := make(chan struct{})
+	writeErr := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.buf = s.sealAt(j.buf[:0], j.buf, j.index, j.last)
+				close(j.done)
+			}
+		}()
+	}
+	go func() {
+		var err error
+		for j := range ordered {
+			<-j.done
+			if err != nil {
+				continue
+			}
+			if _, err = out.Write(j.buf); err != nil {
+				close(stop)
+			}
+		}
+		writeErr <- err
+	}()
+
+	err := readChunks(in, chunkLen, func(index uint32, last bool, buf []byte) bool {
+		j := &sealJob{index: index, last: last, buf: buf, done: make(chan struct{})}
+		select {
+		case ordered <- j:
+		case <-stop:
+			return false
+		}
+		jobs <- j
+		return true
+	})
+	close(jobs)
+	close(ordered)
+	wg.Wait()
+
+	if werr := <-writeErr; werr != nil {
+		return werr
+	}
+	return err
+}
+
+// readChunks passes the chunks of in to fn in order until the last one or
+// until fn returns false. The last chunk may be empty. Every buffer has
+// room for the tag.
+func readChunks(in io.Reader, chunkLen int, fn func(index uint32, last bool, buf []byte) bool) error {
+	read := func() ([]byte, bool, error) {
+		buf := make([]byte, chunkLen, chunkLen+SymmetricTagLen)
+		n, err := io.ReadFull(in, buf)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return buf[:n], true, nil
+		}
+		return buf, false, err
+	}
+
+	cur, last, err := read()
+	if err != nil {
+		return err
+	}
+	for index := uint32(0); ; index++ {
+		var next []byte
+		var nextLast bool
+		if !last {
+			if next, nextLast, err = read(); err != nil {
+				return err
+			}
+			// a full chunk followed by nothing is the last one
+			last = nextLast && len(next) == 0
+		}
+		if !last && index == math.MaxUint32 {
+			return ErrSymmetricStreamTooLong
+		}
+		if !fn(index, last, cur) || last {
+			return nil
+		}
+		cur, last = next, nextLast
+	}
+}
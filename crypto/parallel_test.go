@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestSealChunksParallel(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, SymmetricKeyLen)
+	wrappedKey := []byte("wrapped key")
+
+	for _, size := range []int{0, 1, 16, 17, 32, 1000} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		header, s, err := newChunkedStream(SymmetricAES256GCM, key, wrappedKey, 16)
+		require.NoError(t, err)
+
+		parallel := bytes.NewBuffer(append([]byte{}, header...))
+		require.NoError(t, sealChunksParallel(bytes.NewReader(data), parallel, s, 16, 3))
+
+		// the output is the same as written by the sequential writer
+		sequential := &bytes.Buffer{}
+		seq, err := newSymmetricStream(SymmetricAES256GCM, key, s.noncePrefix, s.ad)
+		require.NoError(t, err)
+		w := newSymmetricEncryptWriter(sequential, header, seq, 16)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.Equal(t, sequential.Bytes(), parallel.Bytes(), "%d", size)
+
+		d, err := openChunkedTestMessage(key, parallel.Bytes())
+		require.NoError(t, err)
+		decrypted := make([]byte, d.Size())
+		_, err = d.ReadAt(decrypted, 0)
+		require.NoError(t, err)
+		require.Equal(t, data, decrypted)
+	}
+}
+
+func TestSealChunksParallel_WriteError(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, SymmetricKeyLen)
+	_, s, err := newChunkedStream(SymmetricChaCha20Poly1305, key, nil, 16)
+	require.NoError(t, err)
+
+	err = sealChunksParallel(bytes.NewReader(make([]byte, 1000)), &failingWriter{n: 2}, s, 16, 2)
+	require.EqualError(t, err, "write failed")
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"io"
+)
+
+// EncryptWithPasswords encrypts data for everyone who knows one of passwords
+// and for the key recipients. The message can be decrypted with
+// DecryptWithPassword or, by key recipients, with Decrypt.
+func (c *Crypto) EncryptWithPasswords(data []byte, passwords [][]byte, recipients ...PublicKey) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	buffer.Grow(len(data))
+	if err := c.EncryptStreamWithPasswords(bytes.NewReader(data), buffer, passwords, recipients...); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (c *Crypto) EncryptStreamWithPasswords(in io.Reader, out io.Writer, passwords [][]byte, recipients ...PublicKey) error {
+	if len(passwords) == 0 && len(recipients) == 0 {
+		return ErrNoRecipients
+	}
+	for _, p := range passwords {
+		if len(p) == 0 {
+			return ErrPasswordIsEmpty
+		}
+	}
+
+	cipher := c.setupCipher(false)
+	defer deleteObjects(cipher)
+
+	for _, p := range passwords {
+		cipher.AddPasswordRecipient(p)
+	}
+	if err := c.setupRecipients(cipher, recipients); err != nil {
+		return err
+	}
+
+	return copyClose(NewEncryptWriter(NopWriteCloser(out), cipher), in)
+}
+
+func (c *Crypto) DecryptWithPassword(data []byte, password []byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := c.DecryptStreamWithPassword(bytes.NewReader(data), buffer, password); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (c *Crypto) DecryptStreamWithPassword(in io.Reader, out io.Writer, password []byte) error {
+	if len(password) == 0 {
+		return ErrPasswordIsEmpty
+	}
+
+	cipher := c.setupCipher(false)
+	defer deleteObjects(cipher)
+
+	if err := cipher.StartDecryptionWithPassword(password, nil); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(out, NewDecryptReader(in, cipher))
+	return err
+}
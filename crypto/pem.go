@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+)
+
+// PEM block types produced by ExportPrivateKeyPEM and ExportPublicKeyPEM.
+// The blocks have no headers, as RFC 7468 requires; the key type is written
+// as a "Key-Type: <type>" line of explanatory text before the block, which
+// PEM parsers such as OpenSSL skip.
+const (
+	PEMPrivateKeyType = "PRIVATE KEY"
+	PEMPublicKeyType  = "PUBLIC KEY"
+	PEMKeyTypeLabel   = "Key-Type"
+
+	pemECPrivateKeyType  = "EC PRIVATE KEY"
+	pemRSAPrivateKeyType = "RSA PRIVATE KEY"
+)
+
+// ExportPrivateKeyPEM exports the private key as a PKCS#8 "PRIVATE KEY" PEM
+// block preceded by the key type label.
+func (c *Crypto) ExportPrivateKeyPEM(key PrivateKey) ([]byte, error) {
+	der, err := c.ExportPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(der)
+
+	return encodeKeyPEM(PEMPrivateKeyType, der, key.KeyType()), nil
+}
+
+// ExportPublicKeyPEM exports the public key as a SubjectPublicKeyInfo
+// "PUBLIC KEY" PEM block preceded by the key type label.
+func (c *Crypto) ExportPublicKeyPEM(key PublicKey) ([]byte, error) {
+	der, err := c.ExportPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return encodeKeyPEM(PEMPublicKeyType, der, key.KeyType()), nil
+}
+
+// ImportPrivateKeyPEM imports the first private key block found in data.
+// Besides PKCS#8 it accepts the legacy "EC PRIVATE KEY" (SEC 1) and
+// "RSA PRIVATE KEY" (PKCS#1) blocks written by OpenSSL. If the block is
+// preceded by a key type label, the imported key must be of that type.
+func (c *Crypto) ImportPrivateKeyPEM(data []byte) (PrivateKey, error) {
+	block, label, err := findPEMBlock(data, PEMPrivateKeyType, pemECPrivateKeyType, pemRSAPrivateKeyType)
+	if err != nil {
+		return nil, err
+	}
+	der, err := privateKeyPKCS8(block)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(der)
+
+	key, err := c.ImportPrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPEMKeyType(label, key.KeyType()); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ImportPublicKeyPEM imports the first "PUBLIC KEY" block found in data.
+func (c *Crypto) ImportPublicKeyPEM(data []byte) (PublicKey, error) {
+	block, label, err := findPEMBlock(data, PEMPublicKeyType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.ImportPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPEMKeyType(label, key.KeyType()); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func encodeKeyPEM(blockType string, der []byte, kt KeyType) []byte {
+	buf := bytes.NewBufferString(PEMKeyTypeLabel + ": " + kt.String() + "\n")
+	buf.Write(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+	return buf.Bytes()
+}
+
+var pemBegin = []byte("-----BEGIN ")
+
+// findPEMBlock returns the first block of one of the types and the key type
+// label found in the text between the previous block and this one
+func findPEMBlock(data []byte, types ...string) (*pem.Block, string, error) {
+	for {
+		start := bytes.Index(data, pemBegin)
+		if start < 0 {
+			return nil, "", ErrPEMBlockNotFound
+		}
+		label := pemKeyTypeLabel(data[:start])
+
+		var block *pem.Block
+		block, data = pem.Decode(data[start:])
+		if block == nil {
+			return nil, "", ErrPEMBlockNotFound
+		}
+		for _, t := range types {
+			if block.Type == t {
+				return block, label, nil
+			}
+		}
+	}
+}
+
+func pemKeyTypeLabel(text []byte) string {
+	var label string
+	lines := bufio.NewScanner(bytes.NewReader(text))
+	for lines.Scan() {
+		if v, ok := strings.CutPrefix(lines.Text(), PEMKeyTypeLabel+":"); ok {
+			label = strings.TrimSpace(v)
+		}
+	}
+	return label
+}
+
+// privateKeyPKCS8 converts legacy OpenSSL private key blocks to PKCS#8
+func privateKeyPKCS8(block *pem.Block) ([]byte, error) {
+	switch block.Type {
+	case pemECPrivateKeyType:
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKCS8PrivateKey(key)
+	case pemRSAPrivateKeyType:
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKCS8PrivateKey(key)
+	default:
+		return append([]byte(nil), block.Bytes...), nil
+	}
+}
+
+func checkPEMKeyType(label string, kt KeyType) error {
+	if label != "" && label != kt.String() {
+		return ErrPEMKeyTypeMismatch
+	}
+	return nil
+}
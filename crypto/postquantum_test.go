@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+const pqVectorsFile = "testdata/postquantum_vectors.json"
+
+// pqVector is a FIPS 203 or FIPS 204 key generation seed and the public key
+// an independent implementation derives from it. Source tells where both come
+// from.
+type pqVector struct {
+	Algorithm string `json:"algorithm"`
+	Source    string `json:"source"`
+	Seed      string `json:"seed"`
+	PublicKey string `json:"public_key"`
+}
+
+// TestPostQuantumKnownAnswers generates ML-KEM and ML-DSA keys from the seeds
+// in testdata and compares the raw public keys with the expected ones. The
+// seed is the only randomness key generation draws: d and z for ML-KEM (the
+// vector uses d = z, so the result does not depend on how the draws are
+// split), ξ for ML-DSA.
+//
+// There are no signature vectors: Sign produces a Virgil signature over a
+// SHA-512 digest with hedged randomness, which no FIPS 204 or FIPS 205 test
+// vector describes. SLH-DSA has no vector because the standard library has no
+// implementation to compute one independently. Signing with every new
+// algorithm is covered by TestPostQuantumKeyTypes.
+func TestPostQuantumKnownAnswers(t *testing.T) {
+	data, err := os.ReadFile(pqVectorsFile)
+	require.NoError(t, err)
+	var vectors []pqVector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	byAlgorithm := make(map[string]pqVector, len(vectors))
+	for _, v := range vectors {
+		byAlgorithm[v.Algorithm] = v
+	}
+
+	c := &Crypto{}
+	for _, alg := range []Algorithm{AlgMlKem1024, AlgMlDsa44, AlgMlDsa87} {
+		t.Run(alg.String(), func(t *testing.T) {
+			v, ok := byAlgorithm[alg.String()]
+			require.True(t, ok)
+			seed, err := hex.DecodeString(v.Seed)
+			require.NoError(t, err)
+
+			rnd := foundation.NewFakeRandom()
+			defer deleteObjects(rnd)
+			rnd.SetupSourceData(seed)
+
+			key, err := c.GenerateKeypairForTypeWithCustomRng(rnd, KeyType{simple: alg})
+			require.NoError(t, err)
+			exported, err := c.ExportPublicKey(key.PublicKey())
+			require.NoError(t, err)
+			var spki struct {
+				Algorithm asn1.RawValue
+				PublicKey asn1.BitString
+			}
+			rest, err := asn1.Unmarshal(exported, &spki)
+			require.NoError(t, err)
+			require.Empty(t, rest)
+			require.Equal(t, v.PublicKey, hex.EncodeToString(spki.PublicKey.Bytes))
+		})
+	}
+}
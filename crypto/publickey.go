@@ -48,7 +48,7 @@ func (k *publicKey) Identifier() []byte {
 
 func (k *publicKey) Export() ([]byte, error) {
 	kp := foundation.NewKeyProvider()
-	defer delete(kp)
+	defer deleteObjects(kp)
 
 	kp.SetRandom(random)
 	if err := kp.SetupDefaults(); err != nil {
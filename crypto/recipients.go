@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+// AddRecipients gives recipients access to a message produced by Encrypt or
+// its signed variants. ownerKey must be a current recipient of the message.
+// Only the header is rewritten, the ciphertext is left untouched.
+func (c *Crypto) AddRecipients(data []byte, ownerKey PrivateKey, recipients ...PublicKey) ([]byte, error) {
+	return c.editRecipients(data, ownerKey, recipients, nil)
+}
+
+// RemoveRecipients revokes access of the recipients with the identifiers.
+// Note that a removed recipient who kept the old header can still decrypt
+// the ciphertext: the data key is not changed.
+func (c *Crypto) RemoveRecipients(data []byte, ownerKey PrivateKey, recipientIDs ...[]byte) ([]byte, error) {
+	return c.editRecipients(data, ownerKey, nil, recipientIDs)
+}
+
+func (c *Crypto) editRecipients(data []byte, ownerKey PrivateKey, add []PublicKey, removeIDs [][]byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	buffer.Grow(len(data))
+	if err := c.EditRecipientsStream(bytes.NewReader(data), buffer, ownerKey, add, removeIDs); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// EditRecipientsStream copies a message from in to out removing the recipients
+// with removeIDs and then adding the add recipients. Recipients already in the
+// message are not added twice. It fails with ErrNoRecipients if nobody would
+// be able to decrypt the result. Password recipients are kept as they are.
+func (c *Crypto) EditRecipientsStream(
+	in io.Reader,
+	out io.Writer,
+	ownerKey PrivateKey,
+	add []PublicKey,
+	removeIDs [][]byte,
+) (err error) {
+	var (
+		serializer *foundation.MessageInfoDerSerializer
+		info       *foundation.MessageInfo
+		editor     *foundation.MessageInfoEditor
+	)
+	defer deleteObjects(serializer, info, editor)
+
+	serializer = foundation.NewMessageInfoDerSerializer()
+	serializer.SetupDefaults()
+
+	header, err := readMessageInfo(in, serializer)
+	if err != nil {
+		return err
+	}
+	if info, err = serializer.Deserialize(header); err != nil {
+		return err
+	}
+
+	recipients := make(map[string]bool)
+	for _, id := range keyRecipientIDs(info) {
+		recipients[string(id)] = true
+	}
+	for _, id := range removeIDs {
+		recipients[string(id)] = false
+	}
+
+	editor = foundation.NewMessageInfoEditor()
+	editor.SetRandom(random)
+	if err = editor.SetupDefaults(); err != nil {
+		return err
+	}
+	if err = editor.Unpack(header); err != nil {
+		return err
+	}
+	if err = editor.Unlock(ownerKey.Identifier(), ownerKey.Unwrap()); err != nil {
+		return err
+	}
+
+	for _, id := range removeIDs {
+		editor.RemoveKeyRecipient(id)
+	}
+	for _, r := range add {
+		if recipients[string(r.Identifier())] {
+			continue
+		}
+		if err = editor.AddKeyRecipient(r.Identifier(), r.Unwrap()); err != nil {
+			return err
+		}
+		recipients[string(r.Identifier())] = true
+	}
+
+	if !hasRecipients(recipients) && passwordRecipientCount(info) == 0 {
+		return ErrNoRecipients
+	}
+
+	if _, err = out.Write(editor.Pack()); err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func hasRecipients(recipients map[string]bool) bool {
+	for _, ok := range recipients {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
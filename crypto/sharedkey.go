@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+// ComputeSharedKey computes the raw Diffie-Hellman shared secret of a
+// Curve25519 or P-256 private key and a public key of the same type. The
+// secret is not uniformly random; derive keys from it with
+// ComputeSharedKeyWithKDF or HKDF.
+func (c *Crypto) ComputeSharedKey(priv PrivateKey, pub PublicKey) ([]byte, error) {
+	if err := checkSharedKeyTypes(priv.KeyType(), pub.KeyType()); err != nil {
+		return nil, err
+	}
+
+	alg, err := foundation.KeyAlgFactoryCreateFromKey(priv.Unwrap(), random)
+	if err != nil {
+		return nil, err
+	}
+	defer deleteObjects(alg)
+
+	dh, ok := alg.(foundation.ComputeSharedKey)
+	if !ok {
+		return nil, ErrUnsupportedKeyType
+	}
+	return dh.ComputeSharedKey(pub.Unwrap(), priv.Unwrap())
+}
+
+// ComputeSharedKeyWithKDF computes the shared secret of the keys and derives
+// length bytes from it with HKDF of the hash type t, salt and info.
+func (c *Crypto) ComputeSharedKeyWithKDF(
+	priv PrivateKey,
+	pub PublicKey,
+	t HashType,
+	salt []byte,
+	info []byte,
+	length int,
+) ([]byte, error) {
+	secret, err := c.ComputeSharedKey(priv, pub)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(secret)
+
+	return c.HKDF(t, secret, salt, info, length)
+}
+
+func checkSharedKeyTypes(priv, pub KeyType) error {
+	if priv != Curve25519 && priv != P256r1 {
+		return ErrUnsupportedKeyType
+	}
+	if priv != pub {
+		return ErrKeyTypeMismatch
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSharedKeyTypes(t *testing.T) {
+	require.NoError(t, checkSharedKeyTypes(Curve25519, Curve25519))
+	require.NoError(t, checkSharedKeyTypes(P256r1, P256r1))
+	require.ErrorIs(t, checkSharedKeyTypes(Curve25519, P256r1), ErrKeyTypeMismatch)
+	require.ErrorIs(t, checkSharedKeyTypes(Ed25519, Ed25519), ErrUnsupportedKeyType)
+	require.ErrorIs(t, checkSharedKeyTypes(Curve25519Ed25519, Curve25519Ed25519), ErrUnsupportedKeyType)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"strconv"
+)
+
+// SignQuorumAll makes DecryptAndVerifySigners and DecryptThenVerifySigners
+// require a verified signature of every signer of the message.
+const SignQuorumAll = 0
+
+// messageSignature is a signature found in an encrypted message
+type messageSignature struct {
+	signerID []byte
+	verify   func(k PublicKey) bool
+}
+
+// verifySignatures checks signatures against verifierKeys and returns the
+// identifiers of the verified signers. Every signer is counted once.
+func verifySignatures(signatures []messageSignature, verifierKeys []PublicKey, quorum int) ([][]byte, error) {
+	if len(signatures) == 0 {
+		return nil, ErrSignNotFound
+	}
+
+	var verified [][]byte
+	seen := make(map[string]bool, len(signatures))
+	for _, s := range signatures {
+		k, err := findVerifyKey(s.signerID, verifierKeys)
+		if err != nil {
+			if quorum == SignQuorumAll {
+				return nil, err
+			}
+			continue
+		}
+		if !s.verify(k) {
+			return nil, ErrSignVerification
+		}
+		if !seen[string(s.signerID)] {
+			seen[string(s.signerID)] = true
+			verified = append(verified, s.signerID)
+		}
+	}
+
+	switch {
+	case len(verified) == 0:
+		return nil, ErrSignNotFound
+	case len(verified) < quorum:
+		return nil, ErrSignQuorumNotReached
+	}
+	return verified, nil
+}
+
+// signerParamKey returns the message info custom param key of the i-th signer.
+// The first signer uses the plain key, so single signer messages stay readable
+// by older versions.
+func signerParamKey(key []byte, i int) []byte {
+	if i == 0 {
+		return key
+	}
+	return append(append([]byte(nil), key...), "-"+strconv.Itoa(i)...)
+}
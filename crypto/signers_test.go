@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testVerifierKey struct {
+	PublicKey
+	id []byte
+}
+
+func (k testVerifierKey) Identifier() []byte {
+	return k.id
+}
+
+func testSignature(id string, valid bool) messageSignature {
+	return messageSignature{signerID: []byte(id), verify: func(PublicKey) bool { return valid }}
+}
+
+func TestVerifySignatures(t *testing.T) {
+	alice := testVerifierKey{id: []byte("alice")}
+	bob := testVerifierKey{id: []byte("bob")}
+	sigs := []messageSignature{
+		testSignature("mallory", true),
+		testSignature("alice", true),
+		testSignature("bob", true),
+		testSignature("alice", true),
+	}
+
+	// the first signer is unknown but a later one is verified
+	verified, err := verifySignatures(sigs, []PublicKey{alice}, 1)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("alice")}, verified)
+
+	verified, err = verifySignatures(sigs, []PublicKey{alice, bob}, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("alice"), []byte("bob")}, verified)
+
+	// duplicate signatures of one signer count once
+	_, err = verifySignatures(sigs, []PublicKey{alice}, 2)
+	require.Equal(t, ErrSignQuorumNotReached, err)
+
+	_, err = verifySignatures(sigs, []PublicKey{alice, bob}, SignQuorumAll)
+	require.Equal(t, ErrSignNotFound, err)
+
+	verified, err = verifySignatures(sigs[1:], []PublicKey{alice, bob}, SignQuorumAll)
+	require.NoError(t, err)
+	require.Len(t, verified, 2)
+
+	_, err = verifySignatures(sigs, []PublicKey{testVerifierKey{id: []byte("eve")}}, 1)
+	require.Equal(t, ErrSignNotFound, err)
+
+	_, err = verifySignatures(nil, []PublicKey{alice}, 1)
+	require.Equal(t, ErrSignNotFound, err)
+
+	// an invalid signature of a known signer is never ignored
+	_, err = verifySignatures(append(sigs, testSignature("bob", false)), []PublicKey{alice, bob}, 1)
+	require.Equal(t, ErrSignVerification, err)
+}
+
+func TestSignerParamKey(t *testing.T) {
+	require.Equal(t, signatureKey, signerParamKey(signatureKey, 0))
+	require.Equal(t, []byte("VIRGIL-DATA-SIGNATURE-2"), signerParamKey(signatureKey, 2))
+	require.Equal(t, []byte("VIRGIL-DATA-SIGNATURE"), signatureKey)
+}
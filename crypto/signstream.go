@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"io"
+
+	"github.com/VirgilSecurity/virgil-crypto-c/wrappers/go/foundation"
+)
+
+func (c *Crypto) SignAndEncryptStream(in io.ReadSeeker, out io.Writer, signer PrivateKey, recipients ...PublicKey) error {
+	return c.SignAndEncryptStreamWithPadding(in, out, signer, false, recipients...)
+}
+
+// SignAndEncryptStreamWithPadding produces the same message as
+// SignAndEncryptWithPadding without keeping the data in memory. The signature
+// is stored in the message header, ahead of the ciphertext, so in is read
+// twice: once to sign the data from the current offset and once to encrypt it.
+func (c *Crypto) SignAndEncryptStreamWithPadding(
+	in io.ReadSeeker,
+	out io.Writer,
+	signer PrivateKey,
+	padding bool,
+	recipients ...PublicKey,
+) (err error) {
+	start, err := in.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	sign, err := c.SignStream(in, signer)
+	if err != nil {
+		return err
+	}
+	if _, err = in.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	var (
+		cipher *foundation.RecipientCipher
+		params *foundation.MessageInfoCustomParams
+	)
+	defer deleteObjects(cipher, params)
+
+	cipher, err = c.setupEncryptCipher(recipients, padding)
+	if err != nil {
+		return err
+	}
+
+	params = cipher.CustomParams()
+	params.AddData(signatureKey, sign)
+	params.AddData(signerIDKey, signer.Identifier())
+
+	if err = cipher.StartEncryption(); err != nil {
+		return err
+	}
+
+	return copyClose(NewEncryptWriter(NopWriteCloser(out), cipher), in)
+}
+
+func (c *Crypto) DecryptAndVerifyStream(in io.Reader, out io.Writer, decryptionKey PrivateKey, verifierKeys ...PublicKey) error {
+	_, err := c.DecryptAndVerifyStreamSigners(in, out, decryptionKey, 1, verifierKeys...)
+	return err
+}
+
+// DecryptAndVerifyStreamSigners decrypts a message produced by SignAndEncrypt,
+// SignAndEncryptWithSigners or SignAndEncryptStream and verifies its
+// signatures with the same rules as DecryptThenVerifySigners. Plaintext is
+// written to out as it is decrypted and must not be trusted until the
+// function returns without an error.
+func (c *Crypto) DecryptAndVerifyStreamSigners(
+	in io.Reader,
+	out io.Writer,
+	decryptionKey PrivateKey,
+	quorum int,
+	verifierKeys ...PublicKey,
+) (verifiedSigners [][]byte, err error) {
+	if quorum < 0 {
+		return nil, ErrUnsupportedParameter
+	}
+
+	cipher := c.setupCipher(false)
+	sv := &streamVerifier{cipher: cipher}
+	defer deleteObjects(sv, cipher)
+
+	if err = cipher.StartDecryptionWithKey(decryptionKey.Identifier(), decryptionKey.Unwrap(), nil); err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(io.MultiWriter(sv, out), NewDecryptReader(in, cipher)); err != nil {
+		return nil, err
+	}
+
+	signatures, err := sv.signatures()
+	if err != nil {
+		return nil, err
+	}
+	return verifySignatures(signatures, verifierKeys, quorum)
+}
+
+// streamVerifier feeds decrypted data to a verifier per signature stored in
+// the message info custom params. The params are read on the first write,
+// when the message header has been processed by the cipher.
+type streamVerifier struct {
+	cipher    *foundation.RecipientCipher
+	params    *foundation.MessageInfoCustomParams
+	verifiers []*foundation.Verifier
+	signerIDs [][]byte
+	started   bool
+}
+
+func (sv *streamVerifier) start() error {
+	sv.started = true
+	sv.params = sv.cipher.CustomParams()
+	for i := 0; ; i++ {
+		signerID, err := sv.params.FindData(signerParamKey(signerIDKey, i))
+		if err != nil {
+			return nil
+		}
+		sign, err := sv.params.FindData(signerParamKey(signatureKey, i))
+		if err != nil {
+			return err
+		}
+
+		v := foundation.NewVerifier()
+		sv.verifiers = append(sv.verifiers, v)
+		if err = v.Reset(sign); err != nil {
+			return err
+		}
+		sv.signerIDs = append(sv.signerIDs, signerID)
+	}
+}
+
+func (sv *streamVerifier) Write(d []byte) (int, error) {
+	if !sv.started {
+		if err := sv.start(); err != nil {
+			return 0, err
+		}
+	}
+	for _, v := range sv.verifiers {
+		v.AppendData(d)
+	}
+	return len(d), nil
+}
+
+func (sv *streamVerifier) signatures() ([]messageSignature, error) {
+	if !sv.started {
+		if err := sv.start(); err != nil {
+			return nil, err
+		}
+	}
+	signatures := make([]messageSignature, len(sv.signerIDs))
+	for i := range sv.signerIDs {
+		v := sv.verifiers[i]
+		signatures[i] = messageSignature{
+			signerID: sv.signerIDs[i],
+			verify: func(k PublicKey) bool {
+				return v.Verify(k.Unwrap())
+			},
+		}
+	}
+	return signatures, nil
+}
+
+func (sv *streamVerifier) Delete() {
+	for _, v := range sv.verifiers {
+		deleteObjects(v)
+	}
+	deleteObjects(sv.params)
+}
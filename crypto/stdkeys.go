@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	gocrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// StdPrivateKey converts an Ed25519, P-256 or RSA private key to its standard
+// library counterpart: ed25519.PrivateKey, *ecdsa.PrivateKey or *rsa.PrivateKey.
+func (c *Crypto) StdPrivateKey(key PrivateKey) (gocrypto.PrivateKey, error) {
+	if !isStdKeyType(key.KeyType()) {
+		return nil, ErrUnsupportedKeyType
+	}
+	der, err := c.ExportPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(der)
+
+	sk, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil && key.KeyType() == P256r1 {
+		// P-256 keys may be exported as SEC 1 instead of PKCS#8
+		return x509.ParseECPrivateKey(der)
+	}
+	return sk, err
+}
+
+// StdPublicKey converts an Ed25519, P-256 or RSA public key to its standard
+// library counterpart: ed25519.PublicKey, *ecdsa.PublicKey or *rsa.PublicKey.
+func (c *Crypto) StdPublicKey(key PublicKey) (gocrypto.PublicKey, error) {
+	if !isStdKeyType(key.KeyType()) {
+		return nil, ErrUnsupportedKeyType
+	}
+	der, err := c.ExportPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParsePKIXPublicKey(der)
+}
+
+// ImportStdPrivateKey imports an ed25519.PrivateKey, a P-256 *ecdsa.PrivateKey
+// or an *rsa.PrivateKey.
+func (c *Crypto) ImportStdPrivateKey(key gocrypto.PrivateKey) (PrivateKey, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey, *rsa.PrivateKey:
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedKeyType
+		}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(der)
+
+	return c.ImportPrivateKey(der)
+}
+
+// ImportStdPublicKey imports an ed25519.PublicKey, a P-256 *ecdsa.PublicKey
+// or an *rsa.PublicKey.
+func (c *Crypto) ImportStdPublicKey(key gocrypto.PublicKey) (PublicKey, error) {
+	switch k := key.(type) {
+	case ed25519.PublicKey, *rsa.PublicKey:
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedKeyType
+		}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.ImportPublicKey(der)
+}
+
+// NewSigner returns a crypto.Signer for the private key, so that it can be
+// used with crypto/tls, crypto/x509 and other standard library APIs.
+// Signatures are produced in the standard format of the algorithm, not in
+// the format of Crypto.Sign.
+func (c *Crypto) NewSigner(key PrivateKey) (gocrypto.Signer, error) {
+	sk, err := c.StdPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := sk.(gocrypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKeyType
+	}
+	return signer, nil
+}
+
+func isStdKeyType(kt KeyType) bool {
+	return kt == Ed25519 || kt == P256r1 || kt.rsaBitlen > 0
+}
@@ -72,6 +72,13 @@ func (sw *EncryptWriter) Write(d []byte) (int, error) {
 }
 
 func (sw *EncryptWriter) Close() error {
+	// nothing was written for empty data, the header is still required
+	if sw.writeHeader {
+		if _, err := sw.w.Write(sw.cipher.PackMessageInfo()); err != nil {
+			return err
+		}
+		sw.writeHeader = false
+	}
 	f, err := sw.cipher.FinishEncryption()
 	if err != nil {
 		return err
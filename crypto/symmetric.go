@@ -0,0 +1,428 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// SymmetricAlgorithm is an AEAD cipher used with a 256-bit symmetric key.
+type SymmetricAlgorithm byte
+
+const (
+	SymmetricAES256GCM        SymmetricAlgorithm = 1
+	SymmetricChaCha20Poly1305 SymmetricAlgorithm = 2
+)
+
+const (
+	SymmetricKeyLen   = 32
+	SymmetricNonceLen = 12
+	SymmetricTagLen   = 16
+
+	// DefaultSymmetricChunkLen is the plaintext length of a chunk written by
+	// SymmetricEncryptStream
+	DefaultSymmetricChunkLen = 64 * 1024
+	// MaxSymmetricChunkLen is the largest chunk length accepted by the stream reader
+	MaxSymmetricChunkLen = 16 * 1024 * 1024
+)
+
+func (a SymmetricAlgorithm) String() string {
+	switch a {
+	case SymmetricAES256GCM:
+		return "AES-256-GCM"
+	case SymmetricChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	default:
+		return "None"
+	}
+}
+
+// NewAEAD returns the AEAD cipher of the algorithm keyed with a SymmetricKeyLen key.
+func NewAEAD(alg SymmetricAlgorithm, key []byte) (cipher.AEAD, error) {
+	if len(key) != SymmetricKeyLen {
+		return nil, ErrInvalidSymmetricKey
+	}
+	switch alg {
+	case SymmetricAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case SymmetricChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, ErrUnsupportedSymmetricAlgorithm
+	}
+}
+
+func (c *Crypto) GenerateSymmetricKey() ([]byte, error) {
+	key := make([]byte, SymmetricKeyLen)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SymmetricSeal encrypts and authenticates plaintext and authenticates ad.
+// The result is a random nonce followed by the ciphertext and the tag.
+func (c *Crypto) SymmetricSeal(alg SymmetricAlgorithm, key, plaintext, ad []byte) ([]byte, error) {
+	aead, err := NewAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, SymmetricNonceLen, SymmetricNonceLen+len(plaintext)+SymmetricTagLen)
+	if _, err = io.ReadFull(rand.Reader, out); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out, plaintext, ad), nil
+}
+
+// SymmetricOpen decrypts data produced by SymmetricSeal with the same key and ad.
+func (c *Crypto) SymmetricOpen(alg SymmetricAlgorithm, key, data, ad []byte) ([]byte, error) {
+	aead, err := NewAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < SymmetricNonceLen+SymmetricTagLen {
+		return nil, ErrSymmetricAuthentication
+	}
+	pt, err := aead.Open(nil, data[:SymmetricNonceLen], data[SymmetricNonceLen:], ad)
+	if err != nil {
+		return nil, ErrSymmetricAuthentication
+	}
+	return pt, nil
+}
+
+// SymmetricEncryptStream encrypts in to out in chunks of DefaultSymmetricChunkLen,
+// see NewSymmetricEncryptWriter.
+func (c *Crypto) SymmetricEncryptStream(in io.Reader, out io.Writer, alg SymmetricAlgorithm, key, ad []byte) error {
+	w, err := NewSymmetricEncryptWriter(out, alg, key, ad, DefaultSymmetricChunkLen)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(w, in); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (c *Crypto) SymmetricDecryptStream(in io.Reader, out io.Writer, key, ad []byte) error {
+	r, err := NewSymmetricDecryptReader(in, key, ad)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	return err
+}
+
+// Stream layout:
+//
+//	version(1) | algorithm(1) | chunk length(4) | nonce prefix(7) | chunk | ... | last chunk
+//
+// Every chunk is sealed separately with the nonce
+//
+//	nonce prefix(7) | chunk index(4) | last chunk flag(1)
+//
+// and the header followed by the caller's associated data as the associated
+// data, so chunks cannot be reordered, dropped or truncated at a chunk
+// boundary without failing authentication. The last chunk may be empty.
+const (
+	symmetricStreamVersion        = 1
+	symmetricStreamNoncePrefixLen = 7
+	symmetricStreamHeaderLen      = 1 + 1 + 4 + symmetricStreamNoncePrefixLen
+)
+
+type symmetricStream struct {
+	aead        cipher.AEAD
+	ad          []byte
+	noncePrefix []byte
+	index       uint32
+	done        bool
+}
+
+func newSymmetricStream(alg SymmetricAlgorithm, key, noncePrefix, ad []byte) (*symmetricStream, error) {
+	aead, err := NewAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	return &symmetricStream{
+		aead:        aead,
+		ad:          ad,
+		noncePrefix: noncePrefix,
+	}, nil
+}
+
+func (s *symmetricStream) chunkNonce(index uint32, last bool) []byte {
+	nonce := make([]byte, SymmetricNonceLen)
+	copy(nonce, s.noncePrefix)
+	binary.BigEndian.PutUint32(nonce[symmetricStreamNoncePrefixLen:], index)
+	if last {
+		nonce[SymmetricNonceLen-1] = 1
+	}
+	return nonce
+}
+
+// next returns the index of the next chunk of a sequentially processed stream
+func (s *symmetricStream) next(last bool) (uint32, error) {
+	if s.done {
+		return 0, ErrInvalidSymmetricStream
+	}
+	if last {
+		s.done = true
+	} else if s.index == math.MaxUint32 {
+		return 0, ErrSymmetricStreamTooLong
+	}
+	s.index++
+	return s.index - 1, nil
+}
+
+func (s *symmetricStream) seal(dst, chunk []byte, last bool) ([]byte, error) {
+	index, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.sealAt(dst, chunk, index, last), nil
+}
+
+func (s *symmetricStream) open(dst, chunk []byte, last bool) ([]byte, error) {
+	index, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.openAt(dst, chunk, index, last)
+}
+
+// sealAt and openAt process the chunk with the given index and are safe for concurrent use
+func (s *symmetricStream) sealAt(dst, chunk []byte, index uint32, last bool) []byte {
+	return s.aead.Seal(dst, s.chunkNonce(index, last), chunk, s.ad)
+}
+
+func (s *symmetricStream) openAt(dst, chunk []byte, index uint32, last bool) ([]byte, error) {
+	pt, err := s.aead.Open(dst, s.chunkNonce(index, last), chunk, s.ad)
+	if err != nil {
+		return nil, ErrSymmetricAuthentication
+	}
+	return pt, nil
+}
+
+// NewSymmetricEncryptWriter returns a writer that encrypts everything written
+// to it in chunks of chunkLen bytes. Close must be called to write the last
+// chunk; it does not close w.
+func NewSymmetricEncryptWriter(w io.Writer, alg SymmetricAlgorithm, key, ad []byte, chunkLen int) (*SymmetricEncryptWriter, error) {
+	if chunkLen <= 0 || chunkLen > MaxSymmetricChunkLen {
+		return nil, ErrUnsupportedParameter
+	}
+	header := make([]byte, symmetricStreamHeaderLen)
+	header[0] = symmetricStreamVersion
+	header[1] = byte(alg)
+	binary.BigEndian.PutUint32(header[2:], uint32(chunkLen))
+	if _, err := io.ReadFull(rand.Reader, header[6:]); err != nil {
+		return nil, err
+	}
+	s, err := newSymmetricStream(alg, key, header[6:], symmetricStreamAD(header, ad))
+	if err != nil {
+		return nil, err
+	}
+	return newSymmetricEncryptWriter(w, header, s, chunkLen), nil
+}
+
+func newSymmetricEncryptWriter(w io.Writer, header []byte, s *symmetricStream, chunkLen int) *SymmetricEncryptWriter {
+	return &SymmetricEncryptWriter{
+		w:        w,
+		s:        s,
+		header:   header,
+		chunkLen: chunkLen,
+		buf:      make([]byte, 0, chunkLen+SymmetricTagLen),
+	}
+}
+
+func symmetricStreamAD(header, ad []byte) []byte {
+	return append(append(make([]byte, 0, len(header)+len(ad)), header...), ad...)
+}
+
+type SymmetricEncryptWriter struct {
+	w        io.Writer
+	s        *symmetricStream
+	header   []byte
+	chunkLen int
+	buf      []byte
+	err      error
+}
+
+func (sw *SymmetricEncryptWriter) Write(d []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+	n := 0
+	for len(d) > 0 {
+		// a full chunk is flushed only once more data follows it,
+		// the last chunk is written by Close
+		if len(sw.buf) == sw.chunkLen {
+			if sw.err = sw.flush(false); sw.err != nil {
+				return n, sw.err
+			}
+		}
+		l := copy(sw.buf[len(sw.buf):sw.chunkLen], d)
+		sw.buf = sw.buf[:len(sw.buf)+l]
+		d = d[l:]
+		n += l
+	}
+	return n, nil
+}
+
+func (sw *SymmetricEncryptWriter) Close() error {
+	if sw.err == ErrSymmetricStreamClosed {
+		return nil
+	}
+	if sw.err != nil {
+		return sw.err
+	}
+	if err := sw.flush(true); err != nil {
+		sw.err = err
+		return err
+	}
+	sw.err = ErrSymmetricStreamClosed
+	return nil
+}
+
+func (sw *SymmetricEncryptWriter) flush(last bool) error {
+	if sw.header != nil {
+		if _, err := sw.w.Write(sw.header); err != nil {
+			return err
+		}
+		sw.header = nil
+	}
+	ct, err := sw.s.seal(sw.buf[:0], sw.buf, last)
+	if err != nil {
+		return err
+	}
+	sw.buf = sw.buf[:0]
+	_, err = sw.w.Write(ct)
+	return err
+}
+
+// NewSymmetricDecryptReader reads the stream header from r and returns a
+// reader of the decrypted data. Read returns ErrSymmetricAuthentication if the
+// stream was modified, reordered or truncated.
+func NewSymmetricDecryptReader(r io.Reader, key, ad []byte) (*SymmetricDecryptReader, error) {
+	header := make([]byte, symmetricStreamHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrInvalidSymmetricStream
+		}
+		return nil, err
+	}
+	if header[0] != symmetricStreamVersion {
+		return nil, ErrInvalidSymmetricStream
+	}
+	chunkLen := binary.BigEndian.Uint32(header[2:])
+	if chunkLen == 0 || chunkLen > MaxSymmetricChunkLen {
+		return nil, ErrInvalidSymmetricStream
+	}
+	s, err := newSymmetricStream(SymmetricAlgorithm(header[1]), key, header[6:], symmetricStreamAD(header, ad))
+	if err != nil {
+		return nil, err
+	}
+	return &SymmetricDecryptReader{
+		r: r,
+		s: s,
+		// one byte more than a sealed chunk to tell whether it is the last one
+		enc: make([]byte, 0, int(chunkLen)+SymmetricTagLen+1),
+		out: make([]byte, 0, chunkLen),
+	}, nil
+}
+
+type SymmetricDecryptReader struct {
+	r   io.Reader
+	s   *symmetricStream
+	enc []byte
+	out []byte
+	buf []byte
+	err error
+}
+
+func (dr *SymmetricDecryptReader) Read(d []byte) (int, error) {
+	for len(dr.buf) == 0 {
+		if dr.err != nil {
+			return 0, dr.err
+		}
+		dr.err = dr.next()
+	}
+	n := copy(d, dr.buf)
+	dr.buf = dr.buf[n:]
+	return n, nil
+}
+
+func (dr *SymmetricDecryptReader) next() error {
+	if dr.s.done {
+		return io.EOF
+	}
+	full := cap(dr.enc)
+	n, err := io.ReadFull(dr.r, dr.enc[len(dr.enc):full])
+	dr.enc = dr.enc[:len(dr.enc)+n]
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	last := len(dr.enc) < full
+	chunk := dr.enc
+	if !last {
+		chunk = dr.enc[:full-1]
+	}
+	if len(chunk) < SymmetricTagLen {
+		return ErrInvalidSymmetricStream
+	}
+	pt, err := dr.s.open(dr.out[:0], chunk, last)
+	if err != nil {
+		return err
+	}
+	dr.buf = pt
+	if !last {
+		dr.enc[0] = dr.enc[full-1]
+		dr.enc = dr.enc[:1]
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymmetricSealOpen(t *testing.T) {
+	c := &Crypto{}
+	for _, alg := range []SymmetricAlgorithm{SymmetricAES256GCM, SymmetricChaCha20Poly1305} {
+		t.Run(alg.String(), func(t *testing.T) {
+			key, err := c.GenerateSymmetricKey()
+			require.NoError(t, err)
+
+			data := []byte("symmetric data")
+			ad := []byte("associated data")
+			ct, err := c.SymmetricSeal(alg, key, data, ad)
+			require.NoError(t, err)
+			require.Len(t, ct, SymmetricNonceLen+len(data)+SymmetricTagLen)
+
+			pt, err := c.SymmetricOpen(alg, key, ct, ad)
+			require.NoError(t, err)
+			require.Equal(t, data, pt)
+
+			_, err = c.SymmetricOpen(alg, key, ct, []byte("other data"))
+			require.ErrorIs(t, err, ErrSymmetricAuthentication)
+
+			ct[len(ct)-1] ^= 1
+			_, err = c.SymmetricOpen(alg, key, ct, ad)
+			require.ErrorIs(t, err, ErrSymmetricAuthentication)
+		})
+	}
+
+	_, err := c.SymmetricSeal(SymmetricAES256GCM, make([]byte, 16), nil, nil)
+	require.ErrorIs(t, err, ErrInvalidSymmetricKey)
+	_, err = c.SymmetricSeal(SymmetricAlgorithm(0), make([]byte, SymmetricKeyLen), nil, nil)
+	require.ErrorIs(t, err, ErrUnsupportedSymmetricAlgorithm)
+}
+
+func symmetricEncryptStream(t *testing.T, alg SymmetricAlgorithm, key, ad, data []byte, chunkLen int) []byte {
+	buf := &bytes.Buffer{}
+	w, err := NewSymmetricEncryptWriter(buf, alg, key, ad, chunkLen)
+	require.NoError(t, err)
+	// odd sized writes cross chunk boundaries
+	for len(data) > 0 {
+		n := 7
+		if n > len(data) {
+			n = len(data)
+		}
+		_, err = w.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestSymmetricStream(t *testing.T) {
+	c := &Crypto{}
+	key, err := c.GenerateSymmetricKey()
+	require.NoError(t, err)
+	ad := []byte("associated data")
+
+	for _, alg := range []SymmetricAlgorithm{SymmetricAES256GCM, SymmetricChaCha20Poly1305} {
+		for _, size := range []int{0, 1, 31, 32, 33, 64, 1000} {
+			data := bytes.Repeat([]byte{0xab}, size)
+			ct := symmetricEncryptStream(t, alg, key, ad, data, 32)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, c.SymmetricDecryptStream(bytes.NewReader(ct), out, key, ad))
+			require.Equal(t, data, out.Bytes(), "%s %d", alg, size)
+		}
+	}
+
+	in := bytes.Repeat([]byte("stream"), 100000)
+	ct := &bytes.Buffer{}
+	require.NoError(t, c.SymmetricEncryptStream(bytes.NewReader(in), ct, SymmetricAES256GCM, key, nil))
+	out := &bytes.Buffer{}
+	require.NoError(t, c.SymmetricDecryptStream(ct, out, key, nil))
+	require.Equal(t, in, out.Bytes())
+}
+
+func TestSymmetricStream_Tampering(t *testing.T) {
+	c := &Crypto{}
+	key, err := c.GenerateSymmetricKey()
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte{1}, 100)
+	sealed := 32 + SymmetricTagLen
+	ct := symmetricEncryptStream(t, SymmetricAES256GCM, key, nil, data, 32)
+
+	decrypt := func(ct []byte, ad []byte) error {
+		return c.SymmetricDecryptStream(bytes.NewReader(ct), &bytes.Buffer{}, key, ad)
+	}
+
+	// truncated at a chunk boundary
+	require.ErrorIs(t, decrypt(ct[:symmetricStreamHeaderLen+2*sealed], nil), ErrSymmetricAuthentication)
+	// truncated inside the header or the first tag
+	require.ErrorIs(t, decrypt(ct[:symmetricStreamHeaderLen-1], nil), ErrInvalidSymmetricStream)
+	require.ErrorIs(t, decrypt(ct[:symmetricStreamHeaderLen+SymmetricTagLen-1], nil), ErrInvalidSymmetricStream)
+	// reordered chunks
+	reordered := append([]byte{}, ct[:symmetricStreamHeaderLen]...)
+	reordered = append(reordered, ct[symmetricStreamHeaderLen+sealed:symmetricStreamHeaderLen+2*sealed]...)
+	reordered = append(reordered, ct[symmetricStreamHeaderLen:symmetricStreamHeaderLen+sealed]...)
+	reordered = append(reordered, ct[symmetricStreamHeaderLen+2*sealed:]...)
+	require.ErrorIs(t, decrypt(reordered, nil), ErrSymmetricAuthentication)
+	// modified header and wrong associated data
+	modified := append([]byte{}, ct...)
+	modified[symmetricStreamHeaderLen-1] ^= 1
+	require.ErrorIs(t, decrypt(modified, nil), ErrSymmetricAuthentication)
+	require.ErrorIs(t, decrypt(ct, []byte("ad")), ErrSymmetricAuthentication)
+	// trailing data after the last chunk
+	require.ErrorIs(t, decrypt(append(append([]byte{}, ct...), 0), nil), ErrSymmetricAuthentication)
+
+	require.NoError(t, decrypt(ct, nil))
+}
+
+func TestSymmetricEncryptWriter_Closed(t *testing.T) {
+	key := make([]byte, SymmetricKeyLen)
+	w, err := NewSymmetricEncryptWriter(&bytes.Buffer{}, SymmetricChaCha20Poly1305, key, nil, 16)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("data"))
+	require.ErrorIs(t, err, ErrSymmetricStreamClosed)
+
+	_, err = NewSymmetricEncryptWriter(&bytes.Buffer{}, SymmetricChaCha20Poly1305, key, nil, 0)
+	require.ErrorIs(t, err, ErrUnsupportedParameter)
+}
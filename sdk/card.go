@@ -38,6 +38,7 @@
 package sdk
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
@@ -53,6 +54,11 @@ type Card struct {
 	PreviousCardId string
 	PreviousCard   *Card
 	IsOutdated     bool
+	// IsRevoked is set only for cards revoked through the CardManager that
+	// returned the card or recorded as revoked in its card cache. The Cards
+	// service does not report revocation, so a card revoked elsewhere has
+	// IsRevoked unset.
+	IsRevoked bool
 
 	Signatures      []*CardSignature
 	ContentSnapshot []byte
@@ -70,9 +76,40 @@ type Cards []*Card
 func (c Cards) ExtractPublicKeys() []crypto.PublicKey {
 	var publicKeys []crypto.PublicKey
 	for _, card := range c {
-		if !card.IsOutdated {
+		if !card.IsOutdated && !card.IsRevoked {
 			publicKeys = append(publicKeys, card.PublicKey)
 		}
 	}
 	return publicKeys
 }
+
+// DetachedSignatureVerifier is implemented by crypto.Crypto
+type DetachedSignatureVerifier interface {
+	VerifyDetached(data []byte, sig *crypto.DetachedSignature, candidates ...crypto.PublicKey) (crypto.PublicKey, error)
+}
+
+// VerifyDetached verifies a detached signature with the public keys of the
+// cards and returns the card of the signer. Outdated cards are candidates
+// because the signature may predate a rotation; revoked cards are not.
+func (c Cards) VerifyDetached(v DetachedSignatureVerifier, data []byte, sig *crypto.DetachedSignature) (*Card, error) {
+	var candidates []crypto.PublicKey
+	for _, card := range c {
+		for ; card != nil; card = card.PreviousCard {
+			if !card.IsRevoked {
+				candidates = append(candidates, card.PublicKey)
+			}
+		}
+	}
+	key, err := v.VerifyDetached(data, sig, candidates...)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range c {
+		for ; card != nil; card = card.PreviousCard {
+			if !card.IsRevoked && bytes.Equal(card.PublicKey.Identifier(), key.Identifier()) {
+				return card, nil
+			}
+		}
+	}
+	return nil, crypto.ErrSignNotFound
+}
@@ -0,0 +1,49 @@
+package sdk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+)
+
+type testPublicKey struct {
+	crypto.PublicKey
+	id []byte
+}
+
+func (k testPublicKey) Identifier() []byte {
+	return k.id
+}
+
+// testDetachedVerifier accepts signatures whose SignerID matches a candidate
+type testDetachedVerifier struct{}
+
+func (testDetachedVerifier) VerifyDetached(_ []byte, sig *crypto.DetachedSignature, candidates ...crypto.PublicKey) (crypto.PublicKey, error) {
+	for _, c := range candidates {
+		if bytes.Equal(c.Identifier(), sig.SignerID) {
+			return c, nil
+		}
+	}
+	return nil, crypto.ErrSignNotFound
+}
+
+func TestCards_VerifyDetached(t *testing.T) {
+	old := &Card{Id: "old", PublicKey: testPublicKey{id: []byte("old")}, IsOutdated: true}
+	current := &Card{Id: "current", PublicKey: testPublicKey{id: []byte("current")}, PreviousCard: old}
+	revoked := &Card{Id: "revoked", PublicKey: testPublicKey{id: []byte("revoked")}, IsRevoked: true}
+	cards := Cards{current, revoked}
+
+	card, err := cards.VerifyDetached(testDetachedVerifier{}, nil, &crypto.DetachedSignature{SignerID: []byte("old")})
+	require.NoError(t, err)
+	require.Same(t, old, card)
+
+	card, err = cards.VerifyDetached(testDetachedVerifier{}, nil, &crypto.DetachedSignature{SignerID: []byte("current")})
+	require.NoError(t, err)
+	require.Same(t, current, card)
+
+	_, err = cards.VerifyDetached(testDetachedVerifier{}, nil, &crypto.DetachedSignature{SignerID: []byte("revoked")})
+	require.Equal(t, crypto.ErrSignNotFound, err)
+}
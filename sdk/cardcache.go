@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package sdk
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CardCache stores raw cards that have already passed CardVerifier so
+// CardManager can skip the Cards service for repeated lookups.
+// Implementations must be safe for concurrent use.
+type CardCache interface {
+	Get(key string) (*CardCacheEntry, bool)
+	Set(key string, entry *CardCacheEntry)
+	Delete(keys ...string)
+}
+
+// CardCacheEntry is a cached service response: either a single card
+// returned by GetCard or every card of one identity returned by SearchCards.
+type CardCacheEntry struct {
+	Models []*RawSignedModel
+	// IsOutdated is set for a GetCard response marked as superseded by the service
+	IsOutdated bool
+	// RevokedCardIDs lists the cards of Models that were known to be revoked
+	// when the entry was stored
+	RevokedCardIDs []string
+}
+
+const (
+	cardCacheCardPrefix     = "card:"
+	cardCacheIdentityPrefix = "identity:"
+)
+
+// CardCacheCardKey returns the key CardManager uses for a card fetched by id.
+func CardCacheCardKey(cardID string) string {
+	return cardCacheCardPrefix + cardID
+}
+
+// CardCacheIdentityKey returns the key CardManager uses for all cards of an identity.
+func CardCacheIdentityKey(identity string) string {
+	return cardCacheIdentityPrefix + identity
+}
+
+var _ CardCache = &MemoryCardCache{}
+
+// MemoryCardCache is an in-memory LRU CardCache with a fixed entry lifetime.
+type MemoryCardCache struct {
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+	lock  sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type memoryCardCacheItem struct {
+	key       string
+	entry     *CardCacheEntry
+	expiresAt time.Time
+}
+
+// NewMemoryCardCache returns a cache holding up to size entries, each for at most ttl.
+func NewMemoryCardCache(size int, ttl time.Duration) *MemoryCardCache {
+	if size <= 0 {
+		panic("NewMemoryCardCache: size should be greater 0")
+	}
+	if ttl <= 0 {
+		panic("NewMemoryCardCache: ttl should be greater 0")
+	}
+	return &MemoryCardCache{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (m *MemoryCardCache) Get(key string) (*CardCacheEntry, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := cacheItem(el)
+	if !m.now().Before(item.expiresAt) {
+		m.removeElement(el)
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+	return item.entry, true
+}
+
+func (m *MemoryCardCache) Set(key string, entry *CardCacheEntry) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	expiresAt := m.now().Add(m.ttl)
+	if el, ok := m.items[key]; ok {
+		item := cacheItem(el)
+		item.entry = entry
+		item.expiresAt = expiresAt
+		m.ll.MoveToFront(el)
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryCardCacheItem{key: key, entry: entry, expiresAt: expiresAt})
+	for m.ll.Len() > m.size {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+func (m *MemoryCardCache) Delete(keys ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, key := range keys {
+		if el, ok := m.items[key]; ok {
+			m.removeElement(el)
+		}
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (m *MemoryCardCache) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.ll.Len()
+}
+
+func (m *MemoryCardCache) removeElement(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.items, cacheItem(el).key)
+}
+
+func cacheItem(el *list.Element) *memoryCardCacheItem {
+	item, _ := el.Value.(*memoryCardCacheItem)
+	return item
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCardCache(t *testing.T) {
+	now := time.Now()
+	cache := NewMemoryCardCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	a := &CardCacheEntry{Models: []*RawSignedModel{{ContentSnapshot: []byte("a")}}}
+	b := &CardCacheEntry{IsOutdated: true}
+	c := &CardCacheEntry{}
+
+	cache.Set("a", a)
+	cache.Set("b", b)
+
+	entry, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, a, entry)
+
+	// "b" is the least recently used entry and must be evicted
+	cache.Set("c", c)
+	require.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	require.False(t, ok)
+
+	cache.Delete("a", "unknown")
+	_, ok = cache.Get("a")
+	require.False(t, ok)
+
+	entry, ok = cache.Get("c")
+	require.True(t, ok)
+	require.Equal(t, c, entry)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("c")
+	require.False(t, ok)
+	require.Equal(t, 0, cache.Len())
+}
+
+func TestFilterCardsByType(t *testing.T) {
+	cards := []*Card{{CardType: "a"}, {CardType: "b"}, {CardType: ""}, {CardType: "a"}}
+	filtered := filterCardsByType(cards, []string{"a", ""})
+	require.Len(t, filtered, 3)
+	for _, card := range filtered {
+		require.NotEqual(t, "b", card.CardType)
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"context"
 	"encoding/hex"
 	"net/http"
+	"sync"
 
 	"github.com/VirgilSecurity/virgil-sdk-go/v7"
 
@@ -48,9 +49,13 @@ import (
 	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
 )
 
+// DefaultCardClientMaxParallelRequests limits concurrent requests made by CardClient.GetCards
+const DefaultCardClientMaxParallelRequests = 8
+
 type cardClientOption struct {
-	serviceURL string
-	httpClient *http.Client
+	serviceURL          string
+	httpClient          *http.Client
+	maxParallelRequests int
 }
 
 type CardClientOption func(c *cardClientOption)
@@ -67,29 +72,46 @@ func SetCardClientHTTPClient(httpClient *http.Client) CardClientOption {
 	}
 }
 
+// SetCardClientMaxParallelRequests sets how many cards CardClient.GetCards fetches at once
+func SetCardClientMaxParallelRequests(n int) CardClientOption {
+	return func(c *cardClientOption) {
+		c.maxParallelRequests = n
+	}
+}
+
 type CardClient struct {
-	client *client.Client
+	client              *client.Client
+	maxParallelRequests int
 }
 
 func NewCardsClient(options ...CardClientOption) *CardClient {
 	o := &cardClientOption{
-		serviceURL: "https://api.virgilsecurity.com",
-		httpClient: client.DefaultHTTPClient,
+		serviceURL:          "https://api.virgilsecurity.com",
+		httpClient:          client.DefaultHTTPClient,
+		maxParallelRequests: DefaultCardClientMaxParallelRequests,
 	}
 	for _, opt := range options {
 		opt(o)
 	}
+	if o.maxParallelRequests <= 0 {
+		o.maxParallelRequests = DefaultCardClientMaxParallelRequests
+	}
 
 	return &CardClient{
 		client: client.NewClient(o.serviceURL,
 			client.HTTPClient(o.httpClient),
 			client.VirgilProduct("sdk", virgil.Version),
 		),
+		maxParallelRequests: o.maxParallelRequests,
 	}
 }
 
 func (c *CardClient) PublishCard(rawCard *RawSignedModel, token string) (*RawSignedModel, error) {
-	resp, err := c.client.Send(context.TODO(), &client.Request{
+	return c.PublishCardContext(context.Background(), rawCard, token)
+}
+
+func (c *CardClient) PublishCardContext(ctx context.Context, rawCard *RawSignedModel, token string) (*RawSignedModel, error) {
+	resp, err := c.client.Send(ctx, &client.Request{
 		Method:   http.MethodPost,
 		Endpoint: "/card/v5",
 		Payload:  rawCard,
@@ -114,7 +136,16 @@ type SearchByTypeRequest struct {
 }
 
 func (c *CardClient) SearchCards(identities []string, cardTypes []string, token string) ([]*RawSignedModel, error) {
-	resp, err := c.client.Send(context.TODO(), &client.Request{
+	return c.SearchCardsContext(context.Background(), identities, cardTypes, token)
+}
+
+func (c *CardClient) SearchCardsContext(
+	ctx context.Context,
+	identities []string,
+	cardTypes []string,
+	token string,
+) ([]*RawSignedModel, error) {
+	resp, err := c.client.Send(ctx, &client.Request{
 		Method:   http.MethodPost,
 		Endpoint: "/card/v5/actions/search",
 		Payload: &SearchByTypeRequest{
@@ -136,11 +167,15 @@ func (c *CardClient) SearchCards(identities []string, cardTypes []string, token
 }
 
 func (c *CardClient) RevokeCard(cardID string, token string) error {
+	return c.RevokeCardContext(context.Background(), cardID, token)
+}
+
+func (c *CardClient) RevokeCardContext(ctx context.Context, cardID string, token string) error {
 	if _, err := hex.DecodeString(cardID); err != nil || len(cardID) != 64 {
 		return errors.NewSDKError(ErrInvalidCardID, "action", "CardClient.RevokeCard")
 	}
 
-	_, err := c.client.Send(context.TODO(), &client.Request{
+	_, err := c.client.Send(ctx, &client.Request{
 		Method:   http.MethodPost,
 		Endpoint: "/card/v5/actions/revoke/" + cardID,
 		Payload:  nil,
@@ -151,6 +186,10 @@ func (c *CardClient) RevokeCard(cardID string, token string) error {
 }
 
 func (c *CardClient) GetCard(cardID string, token string) (*RawSignedModel, bool, error) {
+	return c.GetCardContext(context.Background(), cardID, token)
+}
+
+func (c *CardClient) GetCardContext(ctx context.Context, cardID string, token string) (*RawSignedModel, bool, error) {
 	const (
 		SupersededCardIDHTTPHeader      = "X-Virgil-Is-Superseeded"
 		SupersededCardIDHTTPHeaderValue = "true"
@@ -160,7 +199,7 @@ func (c *CardClient) GetCard(cardID string, token string) (*RawSignedModel, bool
 		return nil, false, errors.NewSDKError(ErrInvalidCardID, "action", "CardClient.GetCard", "card_id", cardID)
 	}
 
-	resp, err := c.client.Send(context.TODO(), &client.Request{
+	resp, err := c.client.Send(ctx, &client.Request{
 		Method:   http.MethodGet,
 		Endpoint: "/card/v5/" + cardID,
 		Payload:  nil,
@@ -178,6 +217,49 @@ func (c *CardClient) GetCard(cardID string, token string) (*RawSignedModel, bool
 	return rawCard, outdated, nil
 }
 
+// GetRawCardResult is the outcome of fetching a single card by CardClient.GetCards
+type GetRawCardResult struct {
+	CardID     string
+	RawCard    *RawSignedModel
+	IsOutdated bool
+	Err        error
+}
+
+func (c *CardClient) GetCards(token string, cardIDs ...string) []*GetRawCardResult {
+	return c.GetCardsContext(context.Background(), token, cardIDs...)
+}
+
+// GetCardsContext fetches cards concurrently, at most maxParallelRequests at a time.
+// Results are returned in the order of cardIDs; a failure of one card does not affect others.
+func (c *CardClient) GetCardsContext(ctx context.Context, token string, cardIDs ...string) []*GetRawCardResult {
+	results := make([]*GetRawCardResult, len(cardIDs))
+	sem := make(chan struct{}, c.maxParallelRequests)
+	wg := &sync.WaitGroup{}
+
+	for i, id := range cardIDs {
+		results[i] = &GetRawCardResult{CardID: id}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = errors.NewSDKError(ctx.Err(), "action", "CardClient.GetCards", "card_id", id)
+			continue
+		}
+
+		wg.Add(1)
+		go func(r *GetRawCardResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.RawCard, r.IsOutdated, r.Err = c.GetCardContext(ctx, r.CardID, token)
+		}(results[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
 func (c *CardClient) makeHeader(token string) http.Header {
 	return http.Header{
 		"Authorization": []string{"Virgil " + token},
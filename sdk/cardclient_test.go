@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardClient_GetCards(t *testing.T) {
+	const maxParallel = 3
+	var inFlight, maxInFlight int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		id := strings.TrimPrefix(r.URL.Path, "/card/v5/")
+		if strings.HasPrefix(id, "0") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(id, "2") {
+			w.Header().Set("X-Virgil-Is-Superseeded", "true")
+		}
+		_, _ = w.Write([]byte(`{"content_snapshot":"` + id[:4] + `"}`))
+	}))
+	defer srv.Close()
+
+	ids := []string{
+		strings.Repeat("1", 64),
+		strings.Repeat("0", 64),
+		strings.Repeat("2", 64),
+		"invalid",
+		strings.Repeat("3", 64),
+		strings.Repeat("4", 64),
+		strings.Repeat("5", 64),
+	}
+	cc := NewCardsClient(SetCardClientURL(srv.URL), SetCardClientMaxParallelRequests(maxParallel))
+	results := cc.GetCards("token", ids...)
+
+	require.Len(t, results, len(ids))
+	for i, r := range results {
+		require.Equal(t, ids[i], r.CardID)
+	}
+	require.NoError(t, results[0].Err)
+	require.False(t, results[0].IsOutdated)
+	require.Error(t, results[1].Err)
+	require.NoError(t, results[2].Err)
+	require.True(t, results[2].IsOutdated)
+	require.ErrorIs(t, results[3].Err, ErrInvalidCardID)
+	require.NoError(t, results[6].Err)
+	require.NotNil(t, results[6].RawCard)
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(maxParallel))
+}
@@ -38,7 +38,9 @@
 package sdk
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/VirgilSecurity/virgil-sdk-go/v7/session"
@@ -70,6 +72,15 @@ func CardManagerSetCardClient(cc *CardClient) CardManagerOption {
 	}
 }
 
+// CardManagerSetCardCache enables caching of verified cards returned by
+// GetCard and SearchCards. Cached entries are dropped when this manager
+// publishes or revokes a card of the same identity.
+func CardManagerSetCardCache(cache CardCache) CardManagerOption {
+	return func(c *CardManager) {
+		c.cardCache = cache
+	}
+}
+
 func CardManagerSetSignCallback(callback func(model *RawSignedModel) (signedCard *RawSignedModel, err error)) CardManagerOption {
 	return func(c *CardManager) {
 		c.signCallback = callback
@@ -82,7 +93,13 @@ type CardManager struct {
 	accessTokenProvider session.AccessTokenProvider
 	cardVerifier        CardVerifier
 	cardClient          *CardClient
+	cardCache           CardCache
+	privateKeyStorage   PrivateKeyStorage
 	signCallback        func(model *RawSignedModel) (signedCard *RawSignedModel, err error)
+
+	// revokedCards holds ids of cards revoked through this manager. The Cards
+	// service does not report revocation in GetCard or SearchCards responses.
+	revokedCards sync.Map
 }
 
 func NewCardManager(accessTokenProvider session.AccessTokenProvider, options ...CardManagerOption) *CardManager {
@@ -138,13 +155,17 @@ func (c *CardManager) GenerateRawCard(cardParams *CardParams) (*RawSignedModel,
 }
 
 func (c *CardManager) PublishRawCard(rawSignedModel *RawSignedModel) (card *Card, err error) {
+	return c.PublishRawCardContext(context.Background(), rawSignedModel)
+}
+
+func (c *CardManager) PublishRawCardContext(ctx context.Context, rawSignedModel *RawSignedModel) (card *Card, err error) {
 	var model RawCardContent
 	if err = ParseSnapshot(rawSignedModel.ContentSnapshot, &model); err != nil {
 		return nil, err
 	}
 
 	tokenContext := &session.TokenContext{Service: "cards", Operation: "publish", Identity: model.Identity}
-	token, err := c.accessTokenProvider.GetToken(tokenContext)
+	token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +175,7 @@ func (c *CardManager) PublishRawCard(rawSignedModel *RawSignedModel) (card *Card
 			return nil, err
 		}
 	}
-	rawCard, err := c.cardClient.PublishCard(rawSignedModel, token.String())
+	rawCard, err := c.cardClient.PublishCardContext(ctx, rawSignedModel, token.String())
 	if err != nil {
 		return nil, err
 	}
@@ -167,65 +188,178 @@ func (c *CardManager) PublishRawCard(rawSignedModel *RawSignedModel) (card *Card
 	if err := c.verifyCards(card); err != nil {
 		return nil, err
 	}
+	c.invalidateCache(card.Identity, card.PreviousCardId)
 	return card, nil
 }
 
 func (c *CardManager) PublishCard(cardParams *CardParams) (*Card, error) {
+	return c.PublishCardContext(context.Background(), cardParams)
+}
+
+func (c *CardManager) PublishCardContext(ctx context.Context, cardParams *CardParams) (*Card, error) {
 	rawSignedModel, err := c.GenerateRawCard(cardParams)
 	if err != nil {
 		return nil, err
 	}
-	return c.PublishRawCard(rawSignedModel)
+	return c.PublishRawCardContext(ctx, rawSignedModel)
 }
 
 func (c *CardManager) GetCard(cardID string) (*Card, error) {
+	return c.GetCardContext(context.Background(), cardID)
+}
+
+func (c *CardManager) GetCardContext(ctx context.Context, cardID string) (*Card, error) {
+	if c.cardCache != nil {
+		if entry, ok := c.cardCache.Get(CardCacheCardKey(cardID)); ok && len(entry.Models) == 1 {
+			return c.parseCachedCard(entry)
+		}
+	}
+
 	tokenContext := &session.TokenContext{Identity: "my_default_identity", Operation: "get"}
-	token, err := c.accessTokenProvider.GetToken(tokenContext)
+	token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
+	if err != nil {
+		return nil, err
+	}
+
+	rawCard, isOutdated, err := c.cardClient.GetCardContext(ctx, cardID, token.String())
 	if err != nil {
 		return nil, err
 	}
+	return c.importFetchedCard(cardID, rawCard, isOutdated)
+}
+
+// GetCardResult is the outcome of fetching a single card by CardManager.GetCards
+type GetCardResult struct {
+	CardID string
+	Card   *Card
+	Err    error
+}
+
+func (c *CardManager) GetCards(cardIDs ...string) []*GetCardResult {
+	return c.GetCardsContext(context.Background(), cardIDs...)
+}
+
+// GetCardsContext fetches and verifies several cards concurrently. Results are
+// returned in the order of cardIDs with a separate error for every card.
+func (c *CardManager) GetCardsContext(ctx context.Context, cardIDs ...string) []*GetCardResult {
+	results := make([]*GetCardResult, len(cardIDs))
+	var (
+		misses   []string
+		missRefs []*GetCardResult
+	)
+	for i, id := range cardIDs {
+		results[i] = &GetCardResult{CardID: id}
+		if c.cardCache != nil {
+			if entry, ok := c.cardCache.Get(CardCacheCardKey(id)); ok && len(entry.Models) == 1 {
+				results[i].Card, results[i].Err = c.parseCachedCard(entry)
+				continue
+			}
+		}
+		misses = append(misses, id)
+		missRefs = append(missRefs, results[i])
+	}
+	if len(misses) == 0 {
+		return results
+	}
 
-	rawCard, outdated, err := c.cardClient.GetCard(cardID, token.String())
+	tokenContext := &session.TokenContext{Identity: "my_default_identity", Operation: "get"}
+	token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
 	if err != nil {
+		for _, r := range missRefs {
+			r.Err = err
+		}
+		return results
+	}
+
+	for i, f := range c.cardClient.GetCardsContext(ctx, token.String(), misses...) {
+		if f.Err != nil {
+			missRefs[i].Err = f.Err
+			continue
+		}
+		missRefs[i].Card, missRefs[i].Err = c.importFetchedCard(f.CardID, f.RawCard, f.IsOutdated)
+	}
+	return results
+}
+
+func (c *CardManager) parseCachedCard(entry *CardCacheEntry) (*Card, error) {
+	card, err := ParseRawCard(c.crypto, entry.Models[0], entry.IsOutdated)
+	if err != nil {
+		return nil, err
+	}
+	c.markRevoked(entry.RevokedCardIDs, card)
+	// the cache may be shared with managers using another verifier
+	if err = c.verifyCards(card); err != nil {
 		return nil, err
 	}
+	return card, nil
+}
 
-	card, err := ParseRawCard(c.crypto, rawCard, outdated)
+func (c *CardManager) importFetchedCard(cardID string, rawCard *RawSignedModel, isOutdated bool) (*Card, error) {
+	card, err := ParseRawCard(c.crypto, rawCard, isOutdated)
 	if err != nil {
 		return nil, err
 	}
+	c.markRevoked(nil, card)
 	err = c.verifyCards(card)
 	if err != nil {
 		return nil, err
 	}
+	if c.cardCache != nil {
+		c.cardCache.Set(CardCacheCardKey(cardID), &CardCacheEntry{
+			Models:         []*RawSignedModel{rawCard},
+			IsOutdated:     isOutdated,
+			RevokedCardIDs: revokedCardIDs(card),
+		})
+	}
 	return card, nil
 }
 
 func (c *CardManager) RevokeCard(cardID string) error {
-	card, err := c.GetCard(cardID)
+	return c.RevokeCardContext(context.Background(), cardID)
+}
+
+func (c *CardManager) RevokeCardContext(ctx context.Context, cardID string) error {
+	card, err := c.GetCardContext(ctx, cardID)
 	if err != nil {
 		return err
 	}
 	tokenContext := &session.TokenContext{Identity: card.Identity, Operation: "delete", Service: "cards"}
-	token, err := c.accessTokenProvider.GetToken(tokenContext)
+	token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
 	if err != nil {
 		return err
 	}
-	return c.cardClient.RevokeCard(cardID, token.String())
+	if err = c.cardClient.RevokeCardContext(ctx, cardID, token.String()); err != nil {
+		return err
+	}
+	c.revokedCards.Store(cardID, struct{}{})
+	c.invalidateCache(card.Identity, cardID)
+	return nil
 }
 
 func (c *CardManager) SearchCards(identities ...string) (Cards, error) {
-	return c.SearchCardsWithTypes(identities)
+	return c.SearchCardsWithTypesContext(context.Background(), identities)
+}
+
+func (c *CardManager) SearchCardsContext(ctx context.Context, identities ...string) (Cards, error) {
+	return c.SearchCardsWithTypesContext(ctx, identities)
 }
 
 func (c *CardManager) SearchCardsWithTypes(identities []string, cardTypes ...string) (Cards, error) {
+	return c.SearchCardsWithTypesContext(context.Background(), identities, cardTypes...)
+}
+
+func (c *CardManager) SearchCardsWithTypesContext(ctx context.Context, identities []string, cardTypes ...string) (Cards, error) {
+	if c.cardCache != nil {
+		return c.searchCardsCached(ctx, identities, cardTypes)
+	}
+
 	tokenContext := &session.TokenContext{Identity: "my_default_identity", Operation: "search"}
-	token, err := c.accessTokenProvider.GetToken(tokenContext)
+	token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
 	if err != nil {
 		return nil, err
 	}
 
-	rawCards, err := c.cardClient.SearchCards(identities, cardTypes, token.String())
+	rawCards, err := c.cardClient.SearchCardsContext(ctx, identities, cardTypes, token.String())
 	if err != nil {
 		return nil, err
 	}
@@ -234,6 +368,7 @@ func (c *CardManager) SearchCardsWithTypes(identities []string, cardTypes ...str
 	if err != nil {
 		return nil, err
 	}
+	c.markRevoked(nil, cards...)
 	err = c.verifyCards(cards...)
 	if err != nil {
 		return nil, err
@@ -287,6 +422,133 @@ func (c *CardManager) ImportCard(model *RawSignedModel) (*Card, error) {
 	return cards[0], nil
 }
 
+// searchCardsCached serves each identity from the cache when possible. Misses
+// are fetched in a single request without the type filter so that the full
+// set of cards of every identity can be cached; types are filtered locally.
+func (c *CardManager) searchCardsCached(ctx context.Context, identities []string, cardTypes []string) (Cards, error) {
+	var (
+		models  []*RawSignedModel
+		revoked []string
+		misses  []string
+	)
+	for _, identity := range identities {
+		entry, ok := c.cardCache.Get(CardCacheIdentityKey(identity))
+		if !ok {
+			misses = append(misses, identity)
+			continue
+		}
+		models = append(models, entry.Models...)
+		revoked = append(revoked, entry.RevokedCardIDs...)
+	}
+	cached := len(models)
+
+	if len(misses) != 0 {
+		tokenContext := &session.TokenContext{Identity: "my_default_identity", Operation: "search"}
+		token, err := session.GetTokenContext(ctx, c.accessTokenProvider, tokenContext)
+		if err != nil {
+			return nil, err
+		}
+
+		rawCards, err := c.cardClient.SearchCardsContext(ctx, misses, nil, token.String())
+		if err != nil {
+			return nil, err
+		}
+		cards, err := ParseRawCards(c.crypto, rawCards...)
+		if err != nil {
+			return nil, err
+		}
+		c.markRevoked(nil, cards...)
+		if err = c.verifyCards(cards...); err != nil {
+			return nil, err
+		}
+
+		byIdentity := make(map[string]*CardCacheEntry, len(misses))
+		for _, identity := range misses {
+			byIdentity[identity] = &CardCacheEntry{}
+		}
+		for i, card := range cards {
+			entry, ok := byIdentity[card.Identity]
+			if !ok {
+				continue
+			}
+			entry.Models = append(entry.Models, rawCards[i])
+			entry.RevokedCardIDs = append(entry.RevokedCardIDs, revokedCardIDs(card)...)
+		}
+		for identity, entry := range byIdentity {
+			c.cardCache.Set(CardCacheIdentityKey(identity), entry)
+		}
+		models = append(models, rawCards...)
+		revoked = append(revoked, revokedCardIDs(cards...)...)
+	}
+
+	cards, err := ParseRawCards(c.crypto, models...)
+	if err != nil {
+		return nil, err
+	}
+	c.markRevoked(revoked, cards...)
+	// fetched cards are verified above; the cache may be shared with managers
+	// using another verifier
+	if err = c.verifyCards(cards[:cached]...); err != nil {
+		return nil, err
+	}
+	if len(cardTypes) != 0 {
+		cards = filterCardsByType(cards, cardTypes)
+	}
+	return LinkCards(cards...), nil
+}
+
+func (c *CardManager) invalidateCache(identity string, cardIDs ...string) {
+	if c.cardCache == nil {
+		return
+	}
+	keys := []string{CardCacheIdentityKey(identity)}
+	for _, id := range cardIDs {
+		if id != "" {
+			keys = append(keys, CardCacheCardKey(id))
+		}
+	}
+	c.cardCache.Delete(keys...)
+}
+
+// markRevoked sets IsRevoked on cards revoked through this manager or listed in revokedIDs.
+func (c *CardManager) markRevoked(revokedIDs []string, cards ...*Card) {
+	for _, card := range cards {
+		if _, ok := c.revokedCards.Load(card.Id); ok {
+			card.IsRevoked = true
+			continue
+		}
+		for _, id := range revokedIDs {
+			if card.Id == id {
+				card.IsRevoked = true
+				break
+			}
+		}
+	}
+}
+
+func revokedCardIDs(cards ...*Card) []string {
+	var ids []string
+	for _, card := range cards {
+		if card.IsRevoked {
+			ids = append(ids, card.Id)
+		}
+	}
+	return ids
+}
+
+func filterCardsByType(cards []*Card, cardTypes []string) []*Card {
+	result := cards[:0]
+	for _, card := range cards {
+		for _, t := range cardTypes {
+			if card.CardType == t {
+				result = append(result, card)
+				break
+			}
+		}
+	}
+	return result
+}
+
 func (c *CardManager) verifyCards(cards ...*Card) error {
 	for _, card := range cards {
 		if err := c.cardVerifier.VerifyCard(card); err != nil {
@@ -0,0 +1,243 @@
+package sdk_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/sdk"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/sdk/cardstest"
+)
+
+func publishTestCard(t *testing.T, manager *sdk.CardManager, params *sdk.CardParams) *sdk.Card {
+	t.Helper()
+	var c crypto.Crypto
+	if params.PrivateKey == nil {
+		key, err := c.GenerateKeypair()
+		require.NoError(t, err)
+		params.PrivateKey = key
+	}
+	card, err := manager.PublishCard(params)
+	require.NoError(t, err)
+	return card
+}
+
+func findCard(cards sdk.Cards, id string) *sdk.Card {
+	for _, card := range cards {
+		if card.Id == id {
+			return card
+		}
+	}
+	return nil
+}
+
+// countingTransport counts the requests sent to the Cards service
+type countingTransport struct {
+	n int32
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.n, 1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func (c *countingTransport) count() int {
+	return int(atomic.LoadInt32(&c.n))
+}
+
+func countingCardManager(srv *cardstest.Server, identity string, options ...sdk.CardManagerOption) (*sdk.CardManager, *countingTransport) {
+	transport := &countingTransport{}
+	client := srv.CardClient(sdk.SetCardClientHTTPClient(&http.Client{Transport: transport}))
+	return srv.CardManager(identity, append([]sdk.CardManagerOption{sdk.CardManagerSetCardClient(client)}, options...)...), transport
+}
+
+func TestCardManager_CacheGetCard(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	manager, requests := countingCardManager(srv, "Alice", sdk.CardManagerSetCardCache(sdk.NewMemoryCardCache(16, time.Minute)))
+	card := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice"})
+
+	got, err := manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.False(t, got.IsOutdated)
+	sent := requests.count()
+	got, err = manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.Equal(t, card.Id, got.Id)
+	require.Equal(t, sent, requests.count())
+
+	// publishing a replacement drops the cached previous card
+	newCard := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", PreviousCardId: card.Id})
+	got, err = manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsOutdated)
+
+	// IsOutdated is kept in the cache entry
+	sent = requests.count()
+	got, err = manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsOutdated)
+	require.Equal(t, sent, requests.count())
+
+	// revoking drops the cached card
+	_, err = manager.GetCard(newCard.Id)
+	require.NoError(t, err)
+	require.NoError(t, manager.RevokeCard(newCard.Id))
+	sent = requests.count()
+	got, err = manager.GetCard(newCard.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsRevoked)
+	require.Equal(t, sent+1, requests.count())
+}
+
+func TestCardManager_CacheSearchCards(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	manager, requests := countingCardManager(srv, "Alice", sdk.CardManagerSetCardCache(sdk.NewMemoryCardCache(16, time.Minute)))
+	device := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "device"})
+	backup := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "backup"})
+
+	cards, err := manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+
+	// served from the cache, including the type filter
+	sent := requests.count()
+	cards, err = manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+	cards, err = manager.SearchCardsWithTypes([]string{"Alice"}, "device")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, device.Id, cards[0].Id)
+	cards, err = manager.SearchCardsWithTypes([]string{"Alice"}, "other")
+	require.NoError(t, err)
+	require.Empty(t, cards)
+	require.Equal(t, sent, requests.count())
+
+	// publishing drops the identity entry
+	replaced := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "device", PreviousCardId: device.Id})
+	sent = requests.count()
+	cards, err = manager.SearchCardsWithTypes([]string{"Alice"}, "device")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, replaced.Id, cards[0].Id)
+	require.Equal(t, sent+1, requests.count())
+
+	// revoking drops the identity entry
+	require.NoError(t, manager.RevokeCard(backup.Id))
+	sent = requests.count()
+	cards, err = manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Nil(t, findCard(cards, backup.Id))
+	require.Equal(t, sent+1, requests.count())
+}
+
+func TestCardManager_RevokedState(t *testing.T) {
+	srv := cardstest.NewServer(cardstest.WithRevokedCardsInSearch())
+	defer srv.Close()
+
+	cache := sdk.NewMemoryCardCache(16, time.Minute)
+	manager := srv.CardManager("Alice", sdk.CardManagerSetCardCache(cache))
+	card := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice"})
+	active := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "device"})
+
+	cards, err := manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.False(t, findCard(cards, card.Id).IsRevoked)
+
+	require.NoError(t, manager.RevokeCard(card.Id))
+
+	got, err := manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsRevoked)
+
+	// fetched from the service, then served from the cache
+	for i := 0; i < 2; i++ {
+		cards, err = manager.SearchCards("Alice")
+		require.NoError(t, err)
+		require.True(t, findCard(cards, card.Id).IsRevoked)
+		require.False(t, findCard(cards, active.Id).IsRevoked)
+	}
+
+	// another manager sharing the cache sees the revocation stored in the entries
+	other := srv.CardManager("Alice", sdk.CardManagerSetCardCache(cache))
+	cards, err = other.SearchCards("Alice")
+	require.NoError(t, err)
+	require.True(t, findCard(cards, card.Id).IsRevoked)
+	got, err = other.GetCard(card.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsRevoked)
+
+	// cached cards go through the verifier of the manager reading the cache
+	strict := srv.CardManager("Alice",
+		sdk.CardManagerSetCardCache(cache),
+		sdk.CardManagerSetCardVerifier(srv.CardVerifier(sdk.VirgilCardVerifierRejectRevoked())),
+	)
+	_, err = strict.SearchCards("Alice")
+	require.ErrorIs(t, err, sdk.ErrCardRevoked)
+	_, err = strict.GetCard(card.Id)
+	require.ErrorIs(t, err, sdk.ErrCardRevoked)
+	results := strict.GetCards(card.Id, active.Id)
+	require.ErrorIs(t, results[0].Err, sdk.ErrCardRevoked)
+	require.NoError(t, results[1].Err)
+
+	// without a cache the state comes from the manager itself
+	uncached := srv.CardManager("Alice", sdk.CardManagerSetCardVerifier(srv.CardVerifier(sdk.VirgilCardVerifierRejectRevoked())))
+	second := publishTestCard(t, uncached, &sdk.CardParams{Identity: "Alice"})
+	require.NoError(t, uncached.RevokeCard(second.Id))
+	_, err = uncached.SearchCards("Alice")
+	require.ErrorIs(t, err, sdk.ErrCardRevoked)
+	_, err = uncached.GetCard(second.Id)
+	require.ErrorIs(t, err, sdk.ErrCardRevoked)
+	results = uncached.GetCards(second.Id, active.Id)
+	require.ErrorIs(t, results[0].Err, sdk.ErrCardRevoked)
+	require.NoError(t, results[1].Err)
+}
+
+// typeVerifier rejects cards of one type
+type typeVerifier struct {
+	rejected string
+}
+
+func (v typeVerifier) VerifyCard(card *sdk.Card) error {
+	if card.CardType == v.rejected {
+		return sdk.ErrValidationSignature
+	}
+	return nil
+}
+
+func TestCardManager_GetCards(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	manager := srv.CardManager("Alice")
+	first := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice"})
+	untrusted := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "untrusted"})
+	second := publishTestCard(t, manager, &sdk.CardParams{Identity: "Alice", CardType: "device"})
+
+	ids := []string{second.Id, sdk.GenerateCardID([]byte("unknown")), untrusted.Id, "invalid", first.Id}
+	manager = srv.CardManager("Alice", sdk.CardManagerSetCardVerifier(typeVerifier{rejected: "untrusted"}))
+	results := manager.GetCards(ids...)
+
+	require.Len(t, results, len(ids))
+	for i, r := range results {
+		require.Equal(t, ids[i], r.CardID)
+	}
+	require.NoError(t, results[0].Err)
+	require.Equal(t, second.Id, results[0].Card.Id)
+	require.ErrorIs(t, results[1].Err, errors.ErrEntityNotFound)
+	require.ErrorIs(t, results[2].Err, sdk.ErrValidationSignature)
+	require.Nil(t, results[2].Card)
+	require.ErrorIs(t, results[3].Err, sdk.ErrInvalidCardID)
+	require.NoError(t, results[4].Err)
+	require.Equal(t, first.Id, results[4].Card.Id)
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+)
+
+// rotationStagingSuffix is appended to the key name while the replacing card
+// is being published. A staged key left behind by a failed rotation can be
+// loaded from this name.
+const rotationStagingSuffix = ".rotating"
+
+// PrivateKeyStorage keeps the private keys of card owners. It is implemented
+// by storage.VirgilPrivateKeyStorage. SupportsReplace reports whether Replace
+// overwrites a key atomically; RotateCard refuses storages that cannot.
+type PrivateKeyStorage interface {
+	Store(privateKey crypto.PrivateKey, name string, meta map[string]string) error
+	Load(name string) (crypto.PrivateKey, map[string]string, error)
+	Replace(privateKey crypto.PrivateKey, name string, meta map[string]string) error
+	SupportsReplace() bool
+	Delete(name string) error
+}
+
+// KeyGenerator generates keypairs for card rotation. It is implemented by crypto.Crypto.
+type KeyGenerator interface {
+	GenerateKeypairForType(t crypto.KeyType) (crypto.PrivateKey, error)
+}
+
+// CardManagerSetPrivateKeyStorage sets the storage used by RotateCard.
+func CardManagerSetPrivateKeyStorage(s PrivateKeyStorage) CardManagerOption {
+	return func(c *CardManager) {
+		c.privateKeyStorage = s
+	}
+}
+
+// RotateCard replaces oldCard with a card for a freshly generated key of
+// newKeyType. See RotateCardContext.
+func (c *CardManager) RotateCard(oldCard *Card, keyName string, newKeyType crypto.KeyType) (*Card, error) {
+	return c.RotateCardContext(context.Background(), oldCard, keyName, newKeyType)
+}
+
+// RotateCardContext replaces oldCard with a card for a freshly generated key
+// of newKeyType. keyName is the name of the private key of oldCard in the
+// private key storage. The new key is staged in the storage before publishing
+// and replaces the key under keyName once the card is accepted by the
+// service, so the storage must support atomic replacement. The returned card
+// links oldCard as PreviousCard.
+//
+// If the card was published but the key under keyName could not be replaced,
+// both the new card and an error are returned. The new card is then the only
+// reference to the published card, and its private key stays in the storage
+// under keyName+".rotating" until the caller moves it.
+func (c *CardManager) RotateCardContext(ctx context.Context, oldCard *Card, keyName string, newKeyType crypto.KeyType) (*Card, error) {
+	if oldCard == nil {
+		return nil, ErrCardIsMandatory
+	}
+	if keyName == "" {
+		return nil, ErrKeyNameIsMandatory
+	}
+	if c.privateKeyStorage == nil {
+		return nil, ErrPrivateKeyStorageIsMandatory
+	}
+	if !c.privateKeyStorage.SupportsReplace() {
+		return nil, ErrPrivateKeyStorageReplaceUnsupported
+	}
+	generator, err := c.keyGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	oldKey, meta, err := c.privateKeyStorage.Load(keyName)
+	if err != nil {
+		return nil, errors.NewSDKError(err, "action", "CardManager.RotateCard", "card_id", oldCard.Id)
+	}
+	if err = c.checkCardKey(oldCard, oldKey); err != nil {
+		return nil, errors.NewSDKError(err, "action", "CardManager.RotateCard", "card_id", oldCard.Id)
+	}
+
+	newKey, err := generator.GenerateKeypairForType(newKeyType)
+	if err != nil {
+		return nil, errors.NewSDKError(err, "action", "CardManager.RotateCard", "card_id", oldCard.Id)
+	}
+
+	// the staged key must not exist so concurrent rotations of the same card fail here
+	staged := keyName + rotationStagingSuffix
+	if err = c.privateKeyStorage.Store(newKey, staged, meta); err != nil {
+		return nil, errors.NewSDKError(err, "action", "CardManager.RotateCard", "card_id", oldCard.Id)
+	}
+
+	newCard, err := c.PublishCardContext(ctx, &CardParams{
+		Identity:       oldCard.Identity,
+		CardType:       oldCard.CardType,
+		PrivateKey:     newKey,
+		PreviousCardId: oldCard.Id,
+	})
+	if err != nil {
+		_ = c.privateKeyStorage.Delete(staged)
+		return nil, err
+	}
+
+	if err = c.privateKeyStorage.Replace(newKey, keyName, meta); err != nil {
+		return newCard, errors.NewSDKError(err, "action", "CardManager.RotateCard", "card_id", newCard.Id, "staged_key", staged)
+	}
+	_ = c.privateKeyStorage.Delete(staged)
+
+	newCard.PreviousCard = oldCard
+	oldCard.IsOutdated = true
+	return newCard, nil
+}
+
+func (c *CardManager) checkCardKey(card *Card, key crypto.PrivateKey) error {
+	if card.PublicKey == nil {
+		return ErrCardPublicKeyUnset
+	}
+	expected, err := c.crypto.ExportPublicKey(card.PublicKey)
+	if err != nil {
+		return err
+	}
+	actual, err := c.crypto.ExportPublicKey(key.PublicKey())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		return ErrPrivateKeyMismatch
+	}
+	return nil
+}
+
+func (c *CardManager) keyGenerator() (KeyGenerator, error) {
+	g, ok := c.crypto.(KeyGenerator)
+	if !ok {
+		return nil, ErrKeyGeneratorUnsupported
+	}
+	return g, nil
+}
@@ -0,0 +1,383 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+// Package cardstest provides an in-process fake of the Virgil Cards service
+// for testing code built on sdk.CardManager without network access.
+package cardstest
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/sdk"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/session"
+)
+
+const (
+	supersededCardIDHTTPHeader = "X-Virgil-Is-Superseeded"
+	appKeyID                   = "cardstest-app-key"
+	appID                      = "cardstest-app"
+)
+
+// errors returned by the fake in the format of the real service
+var (
+	errCardNotFound     = &errors.VirgilAPIError{Code: 10001, Message: "Requested card entity not found."}
+	errTokenInvalid     = &errors.VirgilAPIError{Code: 20300, Message: "The Virgil access token was not specified or is invalid."}
+	errIdentityMismatch = &errors.VirgilAPIError{Code: 20304, Message: "The card identity does not match the access token identity."}
+	errRequestInvalid   = &errors.VirgilAPIError{Code: 40000, Message: "The request body is invalid."}
+	errCardInvalid      = &errors.VirgilAPIError{Code: 40001, Message: "The card signature is invalid."}
+	errCardExists       = &errors.VirgilAPIError{Code: 40002, Message: "The card already exists."}
+	errPreviousCard     = &errors.VirgilAPIError{Code: 40003, Message: "The previous card is not found or already superseded."}
+)
+
+type Option func(s *Server)
+
+// WithServiceKey sets the key the fake uses to add the "virgil" signature to published cards.
+func WithServiceKey(key crypto.PrivateKey) Option {
+	return func(s *Server) {
+		s.serviceKey = key
+	}
+}
+
+// WithJwtVerifier makes the fake accept tokens checked by v instead of the ones
+// produced by Server.JwtGenerator.
+func WithJwtVerifier(v *session.JwtVerifier) Option {
+	return func(s *Server) {
+		s.jwtVerifier = v
+	}
+}
+
+// WithCrypto sets the crypto used for key generation, signing and verification.
+func WithCrypto(c *crypto.Crypto) Option {
+	return func(s *Server) {
+		s.crypto = c
+	}
+}
+
+// WithRevokedCardsInSearch makes search return revoked cards too, so that
+// clients can be tested against revoked cards in search results.
+func WithRevokedCardsInSearch() Option {
+	return func(s *Server) {
+		s.searchRevoked = true
+	}
+}
+
+type storedCard struct {
+	model      *sdk.RawSignedModel
+	content    sdk.RawCardContent
+	superseded bool
+	revoked    bool
+}
+
+// Server is a fake Cards service listening on a local httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	crypto       *crypto.Crypto
+	serviceKey   crypto.PrivateKey
+	appKey       crypto.PrivateKey
+	jwtVerifier  *session.JwtVerifier
+	cardVerifier *sdk.VirgilCardVerifier
+	modelSigner  *sdk.ModelSigner
+
+	searchRevoked bool
+
+	lock  sync.RWMutex
+	cards map[string]*storedCard
+	order []string
+}
+
+// NewServer starts a fake Cards service. Unless overridden by options it
+// generates its own service key and application key.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		crypto: &crypto.Crypto{},
+		cards:  make(map[string]*storedCard),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	var err error
+	if s.serviceKey == nil {
+		if s.serviceKey, err = s.crypto.GenerateKeypair(); err != nil {
+			panic(err)
+		}
+	}
+	if s.jwtVerifier == nil {
+		if s.appKey, err = s.crypto.GenerateKeypair(); err != nil {
+			panic(err)
+		}
+		s.jwtVerifier = session.NewJwtVerifier(s.appKey.PublicKey(), appKeyID, &session.VirgilAccessTokenSigner{Crypto: s.crypto})
+	}
+	s.cardVerifier = sdk.NewVirgilCardVerifier(
+		sdk.VirgilCardVerifierSetCrypto(s.crypto),
+		sdk.VirgilCardVerifierDisableVirgilSignature(),
+	)
+	s.modelSigner = &sdk.ModelSigner{Crypto: s.crypto}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ServicePublicKey returns the service public key in the form accepted by
+// sdk.VirgilCardVerifierSetCardsServicePublicKey.
+func (s *Server) ServicePublicKey() string {
+	pub, err := s.crypto.ExportPublicKey(s.serviceKey.PublicKey())
+	if err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// CardClient returns a client talking to the fake.
+func (s *Server) CardClient(options ...sdk.CardClientOption) *sdk.CardClient {
+	return sdk.NewCardsClient(append([]sdk.CardClientOption{sdk.SetCardClientURL(s.URL)}, options...)...)
+}
+
+// CardVerifier returns a verifier that trusts the fake service key.
+func (s *Server) CardVerifier(options ...sdk.VirgilCardVerifierOption) *sdk.VirgilCardVerifier {
+	return sdk.NewVirgilCardVerifier(append([]sdk.VirgilCardVerifierOption{
+		sdk.VirgilCardVerifierSetCrypto(s.crypto),
+		sdk.VirgilCardVerifierSetCardsServicePublicKey(s.ServicePublicKey()),
+	}, options...)...)
+}
+
+// JwtGenerator returns a generator of tokens accepted by the fake.
+// It panics if the server was created WithJwtVerifier.
+func (s *Server) JwtGenerator() session.JwtGenerator {
+	if s.appKey == nil {
+		panic("cardstest: JwtGenerator is not available with a custom JwtVerifier")
+	}
+	return session.JwtGenerator{
+		AppKey:            s.appKey,
+		AppKeyID:          appKeyID,
+		AppID:             appID,
+		AccessTokenSigner: &session.VirgilAccessTokenSigner{Crypto: s.crypto},
+		TTL:               time.Hour,
+	}
+}
+
+// CardManager returns a manager wired to the fake that authenticates as identity.
+func (s *Server) CardManager(identity string, options ...sdk.CardManagerOption) *sdk.CardManager {
+	provider := session.NewGeneratorJwtProvider(s.JwtGenerator(), session.SetGeneratorJwtProviderDefaultIdentity(identity))
+	return sdk.NewCardManager(provider, append([]sdk.CardManagerOption{
+		sdk.CardManagerSetCrypto(s.crypto),
+		sdk.CardManagerSetCardClient(s.CardClient()),
+		sdk.CardManagerSetCardVerifier(s.CardVerifier()),
+	}, options...)...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	identity, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, errTokenInvalid)
+		return
+	}
+
+	const (
+		cardsPath  = "/card/v5"
+		searchPath = "/card/v5/actions/search"
+		revokePath = "/card/v5/actions/revoke/"
+	)
+	switch p := r.URL.Path; {
+	case r.Method == http.MethodPost && p == cardsPath:
+		s.publish(w, r, identity)
+	case r.Method == http.MethodPost && p == searchPath:
+		s.search(w, r)
+	case r.Method == http.MethodPost && strings.HasPrefix(p, revokePath):
+		s.revoke(w, strings.TrimPrefix(p, revokePath), identity)
+	case r.Method == http.MethodGet && strings.HasPrefix(p, cardsPath+"/"):
+		s.get(w, strings.TrimPrefix(p, cardsPath+"/"))
+	default:
+		writeError(w, http.StatusNotFound, errCardNotFound)
+	}
+}
+
+func (s *Server) authenticate(r *http.Request) (identity string, err error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Virgil ")
+	jwt, err := session.JwtFromString(token)
+	if err != nil {
+		return "", err
+	}
+	if err = jwt.IsExpired(); err != nil {
+		return "", err
+	}
+	if err = s.jwtVerifier.VerifyToken(jwt); err != nil {
+		return "", err
+	}
+	return jwt.Identity()
+}
+
+func (s *Server) publish(w http.ResponseWriter, r *http.Request, identity string) {
+	var model sdk.RawSignedModel
+	if err := json.NewDecoder(r.Body).Decode(&model); err != nil {
+		writeError(w, http.StatusBadRequest, errRequestInvalid)
+		return
+	}
+	var content sdk.RawCardContent
+	if err := sdk.ParseSnapshot(model.ContentSnapshot, &content); err != nil {
+		writeError(w, http.StatusBadRequest, errRequestInvalid)
+		return
+	}
+	if content.Identity != identity {
+		writeError(w, http.StatusForbidden, errIdentityMismatch)
+		return
+	}
+
+	card, err := sdk.ParseRawCard(s.crypto, &model, false)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errRequestInvalid)
+		return
+	}
+	if err = s.cardVerifier.VerifyCard(card); err != nil {
+		writeError(w, http.StatusBadRequest, errCardInvalid)
+		return
+	}
+	if err = s.modelSigner.Sign(&model, sdk.VirgilSigner, s.serviceKey, nil); err != nil {
+		writeError(w, http.StatusBadRequest, errCardInvalid)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.cards[card.Id]; ok {
+		writeError(w, http.StatusConflict, errCardExists)
+		return
+	}
+	if content.PreviousCardId != "" {
+		prev, ok := s.cards[content.PreviousCardId]
+		if !ok || prev.superseded || prev.content.Identity != content.Identity {
+			writeError(w, http.StatusBadRequest, errPreviousCard)
+			return
+		}
+		prev.superseded = true
+	}
+	s.cards[card.Id] = &storedCard{model: &model, content: content}
+	s.order = append(s.order, card.Id)
+
+	writeJSON(w, http.StatusOK, &model)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	var req sdk.SearchByTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Identities) == 0 {
+		writeError(w, http.StatusBadRequest, errRequestInvalid)
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	models := make([]*sdk.RawSignedModel, 0)
+	for _, id := range s.order {
+		c := s.cards[id]
+		if (c.revoked && !s.searchRevoked) || !contains(req.Identities, c.content.Identity) {
+			continue
+		}
+		if len(req.CardTypes) != 0 && !contains(req.CardTypes, c.content.CardType) {
+			continue
+		}
+		models = append(models, c.model)
+	}
+	writeJSON(w, http.StatusOK, models)
+}
+
+func (s *Server) get(w http.ResponseWriter, cardID string) {
+	if _, err := hex.DecodeString(cardID); err != nil || len(cardID) != 64 {
+		writeError(w, http.StatusBadRequest, errRequestInvalid)
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.cards[cardID]
+	if !ok {
+		writeError(w, http.StatusNotFound, errCardNotFound)
+		return
+	}
+	if c.superseded {
+		w.Header().Set(supersededCardIDHTTPHeader, "true")
+	}
+	writeJSON(w, http.StatusOK, c.model)
+}
+
+func (s *Server) revoke(w http.ResponseWriter, cardID string, identity string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c, ok := s.cards[cardID]
+	if !ok || c.revoked {
+		writeError(w, http.StatusNotFound, errCardNotFound)
+		return
+	}
+	if c.content.Identity != identity {
+		writeError(w, http.StatusForbidden, errIdentityMismatch)
+		return
+	}
+	c.revoked = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// nolint: errcheck
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, apiErr *errors.VirgilAPIError) {
+	writeJSON(w, status, apiErr)
+}
@@ -0,0 +1,135 @@
+package cardstest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/sdk"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/sdk/cardstest"
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/storage"
+)
+
+func TestServer_PublishGetSearchRevoke(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	var c crypto.Crypto
+	manager := srv.CardManager("Alice")
+
+	key, err := c.GenerateKeypair()
+	require.NoError(t, err)
+	card, err := manager.PublishCard(&sdk.CardParams{Identity: "Alice", PrivateKey: key})
+	require.NoError(t, err)
+
+	got, err := manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.Equal(t, card.Id, got.Id)
+	require.False(t, got.IsOutdated)
+
+	// replace the card
+	newKey, err := c.GenerateKeypair()
+	require.NoError(t, err)
+	newCard, err := manager.PublishCard(&sdk.CardParams{Identity: "Alice", PrivateKey: newKey, PreviousCardId: card.Id})
+	require.NoError(t, err)
+
+	got, err = manager.GetCard(card.Id)
+	require.NoError(t, err)
+	require.True(t, got.IsOutdated)
+
+	cards, err := manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, newCard.Id, cards[0].Id)
+	require.Equal(t, card.Id, cards[0].PreviousCardId)
+
+	strict := srv.CardManager("Alice", sdk.CardManagerSetCardVerifier(srv.CardVerifier(sdk.VirgilCardVerifierRejectRevoked())))
+	require.NoError(t, strict.RevokeCard(newCard.Id))
+	cards, err = manager.SearchCards("Alice")
+	require.NoError(t, err)
+	for _, found := range cards {
+		require.NotEqual(t, newCard.Id, found.Id)
+	}
+	_, err = strict.GetCard(newCard.Id)
+	require.ErrorIs(t, err, sdk.ErrCardRevoked)
+
+	// like the real service, the fake does not report revocation, so other
+	// managers do not learn about it
+	revoked, err := manager.GetCard(newCard.Id)
+	require.NoError(t, err)
+	require.False(t, revoked.IsRevoked)
+	require.False(t, revoked.IsOutdated)
+
+	_, err = manager.GetCard(sdk.GenerateCardID([]byte("unknown")))
+	require.ErrorIs(t, err, errors.ErrEntityNotFound)
+}
+
+func TestServer_RejectsForeignIdentity(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	var c crypto.Crypto
+	key, err := c.GenerateKeypair()
+	require.NoError(t, err)
+
+	// the token is issued for Bob, but the card is published for Alice
+	model, err := srv.CardManager("Bob").GenerateRawCard(&sdk.CardParams{Identity: "Alice", PrivateKey: key})
+	require.NoError(t, err)
+	generator := srv.JwtGenerator()
+	jwt, err := generator.GenerateToken("Bob", nil)
+	require.NoError(t, err)
+
+	_, err = srv.CardClient().PublishCard(model, jwt.String())
+	require.Error(t, err)
+}
+
+func TestServer_RotateCard(t *testing.T) {
+	srv := cardstest.NewServer()
+	defer srv.Close()
+
+	var c crypto.Crypto
+	keys := storage.NewVirgilPrivateKeyStorage(&storage.FileStorage{RootDir: t.TempDir()})
+	manager := srv.CardManager("Alice", sdk.CardManagerSetPrivateKeyStorage(keys))
+
+	key, err := c.GenerateKeypair()
+	require.NoError(t, err)
+	require.NoError(t, keys.Store(key, "alice-laptop", map[string]string{"device": "laptop"}))
+	card, err := manager.PublishCard(&sdk.CardParams{Identity: "Alice", PrivateKey: key})
+	require.NoError(t, err)
+
+	newCard, err := manager.RotateCard(card, "alice-laptop", crypto.Curve25519Ed25519)
+	require.NoError(t, err)
+	require.Equal(t, card.Id, newCard.PreviousCardId)
+	require.Same(t, card, newCard.PreviousCard)
+	require.True(t, card.IsOutdated)
+
+	stored, meta, err := keys.Load("alice-laptop")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"device": "laptop"}, meta)
+	require.Equal(t, crypto.Curve25519Ed25519, stored.KeyType())
+	require.Equal(t, newCard.PublicKey.Identifier(), stored.Identifier())
+
+	// the stored key no longer belongs to the old card
+	_, err = manager.RotateCard(card, "alice-laptop", crypto.Ed25519)
+	require.ErrorIs(t, err, sdk.ErrPrivateKeyMismatch)
+
+	// a storage without atomic replacement is refused before publishing
+	plain := storage.NewVirgilPrivateKeyStorage(nonAtomicStorage{&storage.FileStorage{RootDir: t.TempDir()}})
+	require.NoError(t, plain.Store(stored, "alice-laptop", nil))
+	manager = srv.CardManager("Alice", sdk.CardManagerSetPrivateKeyStorage(plain))
+	_, err = manager.RotateCard(newCard, "alice-laptop", crypto.Ed25519)
+	require.ErrorIs(t, err, sdk.ErrPrivateKeyStorageReplaceUnsupported)
+	cards, err := manager.SearchCards("Alice")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, newCard.Id, cards[0].Id)
+	_, _, err = plain.Load("alice-laptop.rotating")
+	require.ErrorIs(t, err, storage.ErrorKeyNotFound)
+}
+
+// nonAtomicStorage hides the Replace method of the wrapped storage
+type nonAtomicStorage struct {
+	storage.Storage
+}
@@ -70,6 +70,16 @@ func VirgilCardVerifierDisableVirgilSignature() VirgilCardVerifierOption {
 	}
 }
 
+// VirgilCardVerifierRejectRevoked makes VerifyCard fail with ErrCardRevoked
+// for cards with IsRevoked set. CardManager sets it only for cards revoked
+// through the same manager or its card cache: the Cards service does not
+// report revocation.
+func VirgilCardVerifierRejectRevoked() VirgilCardVerifierOption {
+	return func(v *VirgilCardVerifier) {
+		v.rejectRevoked = true
+	}
+}
+
 func VirgilCardVerifierAddAllowList(wl *AllowList) VirgilCardVerifierOption {
 	return func(v *VirgilCardVerifier) {
 		v.allowLists = append(v.allowLists, wl)
@@ -86,6 +96,7 @@ type VirgilCardVerifier struct {
 	crypto                *CardCrypto
 	verifySelfSignature   bool
 	verifyVirgilSignature bool
+	rejectRevoked         bool
 	allowLists            []*AllowList
 	virgilPublicKey       crypto.PublicKey
 
@@ -125,6 +136,9 @@ func (v *VirgilCardVerifier) VerifyCard(card *Card) error {
 	if card.PublicKey == nil {
 		return ErrCardPublicKeyUnset
 	}
+	if v.rejectRevoked && card.IsRevoked {
+		return errors.NewSDKError(ErrCardRevoked, "action", "VirgilCardVerifier.VerifyCard", "card_id", card.Id)
+	}
 
 	if v.verifySelfSignature {
 		if err := v.ValidateSignerSignature(card, SelfSigner, card.PublicKey); err != nil {
@@ -160,3 +160,20 @@ func makeRandomCredentials() (crypto.PrivateKey, *VerifierCredentials) {
 		PublicKey: key.PublicKey(),
 	}
 }
+
+func TestVirgilCardVerifier_RejectRevoked(t *testing.T) {
+	_, cred := makeRandomCredentials()
+	card := &Card{Id: "revoked", PublicKey: cred.PublicKey, IsRevoked: true}
+
+	v := NewVirgilCardVerifier(
+		VirgilCardVerifierDisableSelfSignature(),
+		VirgilCardVerifierDisableVirgilSignature(),
+		VirgilCardVerifierRejectRevoked(),
+	)
+	require.ErrorIs(t, v.VerifyCard(card), ErrCardRevoked)
+
+	card.IsRevoked = false
+	require.NoError(t, v.VerifyCard(card))
+
+	require.Empty(t, Cards{{PublicKey: cred.PublicKey, IsRevoked: true}}.ExtractPublicKeys())
+}
@@ -49,6 +49,13 @@ var (
 	ErrCardIsMandatory       = errors.New("card is mandatory")
 	ErrCardPublicKeyUnset    = errors.New("card public key is not set")
 
+	ErrPrivateKeyStorageIsMandatory = errors.New("private key storage is mandatory")
+	ErrPrivateKeyMismatch           = errors.New("private key does not match the card public key")
+	ErrKeyNameIsMandatory           = errors.New("key name is mandatory")
+	ErrKeyGeneratorUnsupported      = errors.New("crypto does not implement KeyGenerator")
+
+	ErrPrivateKeyStorageReplaceUnsupported = errors.New("private key storage does not support atomic replace")
+
 	CSRIdentityEmptyErr        = errors.New("Identity field in CSR is mandatory")
 	CSRSignParamIncorrectErr   = errors.New("CSR signature params incorrect")
 	CSRPublicKeyEmptyErr       = errors.New("Public key field in CSR is mandatory")
@@ -60,4 +67,5 @@ var (
 
 	ErrValidationSignature = errors.New("signature validation error")
 	ErrSignerWasNotFound   = errors.New("signer was not found")
+	ErrCardRevoked         = errors.New("card is revoked")
 )
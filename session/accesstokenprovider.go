@@ -37,10 +37,31 @@
 
 package session
 
+import "context"
+
 type AccessTokenProvider interface {
 	GetToken(context *TokenContext) (AccessToken, error)
 }
 
+// ContextAccessTokenProvider is implemented by providers that can abort
+// token retrieval when ctx is cancelled or its deadline is exceeded.
+type ContextAccessTokenProvider interface {
+	AccessTokenProvider
+	GetTokenContext(ctx context.Context, tokenContext *TokenContext) (AccessToken, error)
+}
+
+// GetTokenContext requests a token from p, passing ctx through when p implements
+// ContextAccessTokenProvider. Other providers are called only if ctx is still alive.
+func GetTokenContext(ctx context.Context, p AccessTokenProvider, tokenContext *TokenContext) (AccessToken, error) {
+	if cp, ok := p.(ContextAccessTokenProvider); ok {
+		return cp.GetTokenContext(ctx, tokenContext)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.GetToken(tokenContext)
+}
+
 type ConstAccessTokenProvider struct {
 	AccessToken AccessToken
 }
@@ -48,3 +69,10 @@ type ConstAccessTokenProvider struct {
 func (a *ConstAccessTokenProvider) GetToken(context *TokenContext) (AccessToken, error) {
 	return a.AccessToken, nil
 }
+
+func (a *ConstAccessTokenProvider) GetTokenContext(ctx context.Context, tokenContext *TokenContext) (AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.AccessToken, nil
+}
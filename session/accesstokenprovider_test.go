@@ -0,0 +1,38 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenContext_Canceled(t *testing.T) {
+	calls := 0
+	providers := []AccessTokenProvider{
+		&ConstAccessTokenProvider{AccessToken: &Jwt{}},
+		NewCallbackJwtProvider(func(context *TokenContext) (*Jwt, error) {
+			calls++
+			return &Jwt{}, nil
+		}),
+		NewCachingJwtProvider(func(context *TokenContext) (*Jwt, error) {
+			calls++
+			return &Jwt{}, nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, p := range providers {
+		token, err := GetTokenContext(ctx, p, &TokenContext{Identity: "Alice"})
+		require.Nil(t, token)
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.Equal(t, 0, calls)
+
+	token, err := GetTokenContext(context.Background(), providers[1], &TokenContext{Identity: "Alice"})
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	require.Equal(t, 1, calls)
+}
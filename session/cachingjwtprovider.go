@@ -38,6 +38,7 @@
 package session
 
 import (
+	stdcontext "context"
 	"sync"
 	"time"
 
@@ -73,8 +74,21 @@ func NewCachingStringJwtProvider(renewTokenCallback func(context *TokenContext)
 }
 
 func (c *CachingJwtProvider) GetToken(context *TokenContext) (AccessToken, error) {
-	if context == nil {
-		return nil, errors.NewSDKError(ErrContextIsMandatory, "action", "CachingJwtProvider.GetToken")
+	return c.getToken(stdcontext.Background(), context, "CachingJwtProvider.GetToken")
+}
+
+// GetTokenContext returns the cached token or renews it unless ctx is done.
+// The renewal callback itself is not interrupted by ctx.
+func (c *CachingJwtProvider) GetTokenContext(ctx stdcontext.Context, tokenContext *TokenContext) (AccessToken, error) {
+	return c.getToken(ctx, tokenContext, "CachingJwtProvider.GetTokenContext")
+}
+
+func (c *CachingJwtProvider) getToken(ctx stdcontext.Context, tokenContext *TokenContext, action string) (AccessToken, error) {
+	if tokenContext == nil {
+		return nil, errors.NewSDKError(ErrContextIsMandatory, "action", action)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.NewSDKError(err, "action", action)
 	}
 
 	// TODO: refactor
@@ -88,9 +102,13 @@ func (c *CachingJwtProvider) GetToken(context *TokenContext) (AccessToken, error
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if c.Jwt == nil || c.Jwt.IsExpiredDelta(5*time.Second) != nil {
-		token, err := c.RenewTokenCallback(context)
+		// the caller could have given up while waiting for the lock
+		if err := ctx.Err(); err != nil {
+			return nil, errors.NewSDKError(err, "action", action)
+		}
+		token, err := c.RenewTokenCallback(tokenContext)
 		if err != nil {
-			return nil, errors.NewSDKError(err, "action", "CachingJwtProvider.GetToken")
+			return nil, errors.NewSDKError(err, "action", action)
 		}
 		c.Jwt = token
 	}
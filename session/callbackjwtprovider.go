@@ -37,7 +37,11 @@
 
 package session
 
-import "github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+import (
+	"context"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+)
 
 type CallbackJwtProvider struct {
 	GetTokenCallback func(context *TokenContext) (*Jwt, error)
@@ -72,3 +76,10 @@ func (c *CallbackJwtProvider) GetToken(context *TokenContext) (AccessToken, erro
 
 	return c.GetTokenCallback(context)
 }
+
+func (c *CallbackJwtProvider) GetTokenContext(ctx context.Context, tokenContext *TokenContext) (AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.NewSDKError(err, "action", "CallbackJwtProvider.GetTokenContext")
+	}
+	return c.GetToken(tokenContext)
+}
@@ -37,7 +37,11 @@
 
 package session
 
-import "github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+import (
+	"context"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+)
 
 type GeneratorJwtProviderOption func(p *GeneratorJwtProvider)
 
@@ -91,3 +95,10 @@ func (g *GeneratorJwtProvider) GetToken(context *TokenContext) (AccessToken, err
 	}
 	return at, nil
 }
+
+func (g *GeneratorJwtProvider) GetTokenContext(ctx context.Context, tokenContext *TokenContext) (AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.NewSDKError(err, "action", "GeneratorJwtProvider.GetTokenContext")
+	}
+	return g.GetToken(tokenContext)
+}
@@ -38,7 +38,6 @@
 package storage
 
 import (
-	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha512"
@@ -47,6 +46,7 @@ import (
 
 	"golang.org/x/crypto/hkdf"
 
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
 	verrors "github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
 )
 
@@ -54,11 +54,11 @@ const (
 	//
 	// KeyLength is the exact key length accepted by NewSymmetricEncryptStorage
 	//
-	KeyLength = 32
+	KeyLength = crypto.SymmetricKeyLen
 
 	symSaltLen  = 32
-	symNonceLen = 12
-	symTagLen   = 16
+	symNonceLen = crypto.SymmetricNonceLen
+	symTagLen   = crypto.SymmetricTagLen
 )
 
 var (
@@ -66,7 +66,8 @@ var (
 
 	ErrEncryptedDataInvalid = errors.New("encrypt data invalid")
 
-	_ Storage = &SymmetricEncryptStorage{}
+	_ Storage  = &SymmetricEncryptStorage{}
+	_ Replacer = &SymmetricEncryptStorage{}
 )
 
 func NewSymmetricEncryptStorage(key [KeyLength]byte, storage Storage) *SymmetricEncryptStorage {
@@ -85,35 +86,42 @@ type SymmetricEncryptStorage struct {
 }
 
 func (s *SymmetricEncryptStorage) Store(key string, val []byte) error {
-	salt := make([]byte, symSaltLen)
-	_, err := io.ReadFull(rand.Reader, salt)
+	ct, err := s.seal(val)
 	if err != nil {
 		return verrors.NewSDKError(err, "action", "SymmetricEncryptStorage.Store")
 	}
+	return s.storage.Store(key, ct)
+}
 
-	kdf := hkdf.New(sha512.New, s.key, salt, encryptInfo)
-
-	keyNonce := make([]byte, KeyLength+symNonceLen)
-	_, err = kdf.Read(keyNonce)
+// Replace encrypts val and replaces the stored value, which requires the
+// underlying storage to implement Replacer.
+func (s *SymmetricEncryptStorage) Replace(key string, val []byte) error {
+	ct, err := s.seal(val)
 	if err != nil {
-		return err
+		return verrors.NewSDKError(err, "action", "SymmetricEncryptStorage.Replace")
 	}
+	return Replace(s.storage, key, ct)
+}
 
-	aesgcm, err := aes.NewCipher(keyNonce[:KeyLength])
-	if err != nil {
-		return err
+// SupportsReplace reports whether the underlying storage supports Replace.
+func (s *SymmetricEncryptStorage) SupportsReplace() bool {
+	return SupportsReplace(s.storage)
+}
+
+func (s *SymmetricEncryptStorage) seal(val []byte) ([]byte, error) {
+	salt := make([]byte, symSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
 	}
 
-	aesGcm, err := cipher.NewGCM(aesgcm)
+	aead, nonce, err := s.aead(salt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	ct := make([]byte, symSaltLen+len(val)+aesGcm.Overhead())
+	ct := make([]byte, symSaltLen, symSaltLen+len(val)+symTagLen)
 	copy(ct, salt)
-
-	aesGcm.Seal(ct[:symSaltLen], keyNonce[KeyLength:], val, nil)
-	return s.Store(key, ct)
+	return aead.Seal(ct, nonce, val, nil), nil
 }
 
 func (s *SymmetricEncryptStorage) Load(key string) ([]byte, error) {
@@ -125,27 +133,28 @@ func (s *SymmetricEncryptStorage) Load(key string) ([]byte, error) {
 		return nil, verrors.NewSDKError(ErrEncryptedDataInvalid, "action", "SymmetricEncryptStorage.Load", "key", key)
 	}
 
-	salt := data[:symSaltLen]
-	kdf := hkdf.New(sha512.New, s.key, salt, encryptInfo)
+	aead, nonce, err := s.aead(data[:symSaltLen])
+	if err != nil {
+		return nil, verrors.NewSDKError(err, "action", "SymmetricEncryptStorage.Load", "key", key)
+	}
 
-	keyNonce := make([]byte, KeyLength+symNonceLen)
+	return aead.Open(nil, nonce, data[symSaltLen:], nil)
+}
 
-	if _, err = kdf.Read(keyNonce); err != nil {
-		return nil, err
-	}
+// aead derives the AES-256-GCM key and nonce of a stored value from its salt
+func (s *SymmetricEncryptStorage) aead(salt []byte) (cipher.AEAD, []byte, error) {
+	kdf := hkdf.New(sha512.New, s.key, salt, encryptInfo)
 
-	aesgcm, err := aes.NewCipher(keyNonce[:KeyLength])
-	if err != nil {
-		return nil, err
+	keyNonce := make([]byte, KeyLength+symNonceLen)
+	if _, err := io.ReadFull(kdf, keyNonce); err != nil {
+		return nil, nil, err
 	}
 
-	aesGcm, err := cipher.NewGCM(aesgcm)
+	aead, err := crypto.NewAEAD(crypto.SymmetricAES256GCM, keyNonce[:KeyLength])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	dst := make([]byte, 0)
-	return aesGcm.Open(dst, keyNonce[KeyLength:], data[symSaltLen:], nil)
+	return aead, keyNonce[KeyLength:], nil
 }
 
 func (s *SymmetricEncryptStorage) Exists(key string) bool {
@@ -48,7 +48,8 @@ import (
 )
 
 var (
-	_ Storage = &FileStorage{}
+	_ Storage  = &FileStorage{}
+	_ Replacer = &FileStorage{}
 )
 
 type FileStorage struct {
@@ -67,6 +68,39 @@ func (s *FileStorage) Store(key string, val []byte) error {
 	return ioutil.WriteFile(path.Join(dir, key), val, 0600)
 }
 
+// Replace writes val to a temporary file and renames it over the existing key.
+func (s *FileStorage) Replace(key string, val []byte) error {
+	dir, err := s.getRootDir()
+	if err != nil {
+		return err
+	}
+	if !s.Exists(key) {
+		return ErrorKeyNotFound
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+key+".*")
+	if err != nil {
+		return errors.NewSDKError(err, "action", "FileStorage.Replace", "key", key)
+	}
+	defer os.Remove(tmp.Name()) //nolint: errcheck
+
+	if _, err = tmp.Write(val); err != nil {
+		_ = tmp.Close()
+		return errors.NewSDKError(err, "action", "FileStorage.Replace", "key", key)
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.NewSDKError(err, "action", "FileStorage.Replace", "key", key)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.NewSDKError(err, "action", "FileStorage.Replace", "key", key)
+	}
+	if err = os.Rename(tmp.Name(), path.Join(dir, key)); err != nil {
+		return errors.NewSDKError(err, "action", "FileStorage.Replace", "key", key)
+	}
+	return nil
+}
+
 func (s *FileStorage) Load(name string) ([]byte, error) {
 	dir, err := s.getRootDir()
 	if err != nil {
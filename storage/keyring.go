@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package storage
+
+import (
+	"sync"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+	verrors "github.com/VirgilSecurity/virgil-sdk-go/v7/errors"
+)
+
+var (
+	_ crypto.PrivateKeyFinder = &PrivateKeyStorageKeyring{}
+)
+
+// PrivateKeyStorageKeyring is a keyring backed by VirgilPrivateKeyStorage.
+// Keys are loaded on demand, one name at a time, until the requested
+// identifier is found; loaded keys are kept in memory. Names that fail to
+// load are skipped and reported by LoadErrors.
+type PrivateKeyStorageKeyring struct {
+	storage *VirgilPrivateKeyStorage
+
+	mu      sync.Mutex
+	pending []string
+	failed  map[string]error
+	keyring *crypto.Keyring
+}
+
+// NewPrivateKeyStorageKeyring returns a keyring of the keys stored under names.
+func NewPrivateKeyStorageKeyring(storage *VirgilPrivateKeyStorage, names ...string) *PrivateKeyStorageKeyring {
+	if storage == nil {
+		panic("NewPrivateKeyStorageKeyring: storage is nil")
+	}
+	return &PrivateKeyStorageKeyring{
+		storage: storage,
+		pending: append([]string(nil), names...),
+		failed:  make(map[string]error),
+		keyring: crypto.NewKeyring(),
+	}
+}
+
+func (k *PrivateKeyStorageKeyring) FindPrivateKey(id []byte) (crypto.PrivateKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, err := k.keyring.FindPrivateKey(id); err == nil {
+		return key, nil
+	}
+	for len(k.pending) > 0 {
+		name := k.pending[0]
+		k.pending = k.pending[1:]
+		key, _, err := k.storage.Load(name)
+		if err != nil {
+			k.failed[name] = verrors.NewSDKError(err, "action", "PrivateKeyStorageKeyring.FindPrivateKey", "name", name)
+			continue
+		}
+		k.keyring.Add(key)
+
+		if string(key.Identifier()) == string(id) {
+			return key, nil
+		}
+	}
+	return nil, crypto.ErrKeyNotFound
+}
+
+// LoadErrors returns the errors of the names whose keys could not be loaded so far.
+func (k *PrivateKeyStorageKeyring) LoadErrors() map[string]error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	errs := make(map[string]error, len(k.failed))
+	for name, err := range k.failed {
+		errs[name] = err
+	}
+	return errs
+}
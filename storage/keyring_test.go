@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+)
+
+type testPrivateKey struct {
+	crypto.PrivateKey
+	id []byte
+}
+
+func (k testPrivateKey) Identifier() []byte {
+	return k.id
+}
+
+// testExporter exports a key as its identifier
+type testExporter struct {
+	imported []string
+}
+
+func (e *testExporter) ExportPrivateKey(privateKey crypto.PrivateKey) ([]byte, error) {
+	return privateKey.Identifier(), nil
+}
+
+func (e *testExporter) ImportPrivateKey(data []byte) (crypto.PrivateKey, error) {
+	e.imported = append(e.imported, string(data))
+	return testPrivateKey{id: data}, nil
+}
+
+func TestPrivateKeyStorageKeyring(t *testing.T) {
+	exporter := &testExporter{}
+	s := NewVirgilPrivateKeyStorage(&memoryStorage{data: map[string][]byte{}}, SetPrivateKeyStorageExporter(exporter))
+	for _, id := range []string{"k1", "k2", "k3"} {
+		require.NoError(t, s.Store(testPrivateKey{id: []byte(id)}, "name-"+id, nil))
+	}
+
+	k := NewPrivateKeyStorageKeyring(s, "name-k1", "name-k2", "name-k3")
+
+	key, err := k.FindPrivateKey([]byte("k2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("k2"), key.Identifier())
+	require.Equal(t, []string{"k1", "k2"}, exporter.imported)
+
+	// already loaded keys are not loaded again
+	_, err = k.FindPrivateKey([]byte("k1"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1", "k2"}, exporter.imported)
+
+	_, err = k.FindPrivateKey([]byte("unknown"))
+	require.Equal(t, crypto.ErrKeyNotFound, err)
+	require.Equal(t, []string{"k1", "k2", "k3"}, exporter.imported)
+}
+
+func TestPrivateKeyStorageKeyring_SkipsFailedNames(t *testing.T) {
+	s := NewVirgilPrivateKeyStorage(&memoryStorage{data: map[string][]byte{}}, SetPrivateKeyStorageExporter(&testExporter{}))
+	require.NoError(t, s.Store(testPrivateKey{id: []byte("k1")}, "name-k1", nil))
+
+	k := NewPrivateKeyStorageKeyring(s, "missing", "name-k1")
+
+	key, err := k.FindPrivateKey([]byte("k1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("k1"), key.Identifier())
+
+	errs := k.LoadErrors()
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs["missing"], ErrorKeyNotFound)
+
+	_, err = k.FindPrivateKey([]byte("unknown"))
+	require.Equal(t, crypto.ErrKeyNotFound, err)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2015-2026 Virgil Security Inc.
+ *
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are
+ * met:
+ *
+ *     (1) Redistributions of source code must retain the above copyright
+ *     notice, this list of conditions and the following disclaimer.
+ *
+ *     (2) Redistributions in binary form must reproduce the above copyright
+ *     notice, this list of conditions and the following disclaimer in
+ *     the documentation and/or other materials provided with the
+ *     distribution.
+ *
+ *     (3) Neither the name of the copyright holder nor the names of its
+ *     contributors may be used to endorse or promote products derived from
+ *     this software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE AUTHOR ''AS IS'' AND ANY EXPRESS OR
+ * IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY DIRECT,
+ * INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ * (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+ * HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+ * STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING
+ * IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ *
+ * Lead Maintainer: Virgil Security Inc. <support@virgilsecurity.com>
+ */
+
+package storage
+
+import (
+	"github.com/VirgilSecurity/virgil-sdk-go/v7/crypto"
+)
+
+var (
+	_ PrivateKeyExporter = &PasswordPrivateKeyExporter{}
+)
+
+// PasswordPrivateKeyExporter makes VirgilPrivateKeyStorage keep private keys
+// encrypted with a password instead of a raw symmetric key.
+type PasswordPrivateKeyExporter struct {
+	Crypto   *crypto.Crypto
+	Password []byte
+	// Params of the password KDF, crypto.DefaultPasswordKDFParams if zero
+	Params crypto.PasswordKDFParams
+}
+
+func (e *PasswordPrivateKeyExporter) ExportPrivateKey(privateKey crypto.PrivateKey) ([]byte, error) {
+	params := e.Params
+	if params == (crypto.PasswordKDFParams{}) {
+		params = crypto.DefaultPasswordKDFParams
+	}
+	return e.getCrypto().ExportPrivateKeyWithPasswordParams(privateKey, e.Password, params)
+}
+
+func (e *PasswordPrivateKeyExporter) ImportPrivateKey(data []byte) (crypto.PrivateKey, error) {
+	return e.getCrypto().ImportPrivateKeyWithPassword(data, e.Password)
+}
+
+func (e *PasswordPrivateKeyExporter) getCrypto() *crypto.Crypto {
+	if e.Crypto != nil {
+		return e.Crypto
+	}
+	return &crypto.Crypto{}
+}
@@ -98,16 +98,37 @@ func (v *VirgilPrivateKeyStorage) Validate() error {
 }
 
 func (v *VirgilPrivateKeyStorage) Store(privateKey crypto.PrivateKey, name string, meta map[string]string) error {
-	exported, err := v.privateKeyExporter.ExportPrivateKey(privateKey)
+	data, err := v.marshal(privateKey, meta)
 	if err != nil {
 		return verrors.NewSDKError(err, "action", "VirgilPrivateKeyStorage.Store")
 	}
+	return v.storage.Store(name, data)
+}
 
-	data, err := json.Marshal(storageKeyJSON{Key: exported, Meta: meta})
+// Replace atomically overwrites an already stored private key. The underlying
+// storage must implement Replacer, otherwise ErrorReplaceNotSupported is returned.
+func (v *VirgilPrivateKeyStorage) Replace(privateKey crypto.PrivateKey, name string, meta map[string]string) error {
+	data, err := v.marshal(privateKey, meta)
 	if err != nil {
-		return verrors.NewSDKError(err, "action", "VirgilPrivateKeyStorage.Store")
+		return verrors.NewSDKError(err, "action", "VirgilPrivateKeyStorage.Replace", "name", name)
 	}
-	return v.storage.Store(name, data)
+	if err = Replace(v.storage, name, data); err != nil {
+		return verrors.NewSDKError(err, "action", "VirgilPrivateKeyStorage.Replace", "name", name)
+	}
+	return nil
+}
+
+// SupportsReplace reports whether the underlying storage supports Replace.
+func (v *VirgilPrivateKeyStorage) SupportsReplace() bool {
+	return SupportsReplace(v.storage)
+}
+
+func (v *VirgilPrivateKeyStorage) marshal(privateKey crypto.PrivateKey, meta map[string]string) ([]byte, error) {
+	exported, err := v.privateKeyExporter.ExportPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(storageKeyJSON{Key: exported, Meta: meta})
 }
 
 func (v *VirgilPrivateKeyStorage) Load(name string) (privateKey crypto.PrivateKey, meta map[string]string, err error) {
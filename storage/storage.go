@@ -42,6 +42,8 @@ import "errors"
 var (
 	ErrorKeyAlreadyExists = errors.New("key already exists")
 	ErrorKeyNotFound      = errors.New("key not found")
+
+	ErrorReplaceNotSupported = errors.New("storage does not support atomic replace")
 )
 
 type Storage interface {
@@ -50,3 +52,34 @@ type Storage interface {
 	Exists(key string) bool
 	Delete(key string) error
 }
+
+// Replacer is implemented by storages that can overwrite an existing key atomically
+type Replacer interface {
+	Replace(key string, val []byte) error
+}
+
+// Replace overwrites the value of an existing key. It fails with
+// ErrorReplaceNotSupported unless s implements Replacer: emulating it with
+// Load, Delete and Store could lose the key if the process stops in between.
+func Replace(s Storage, key string, val []byte) error {
+	r, ok := s.(Replacer)
+	if !ok {
+		return ErrorReplaceNotSupported
+	}
+	return r.Replace(key, val)
+}
+
+// replaceSupporter is implemented by storages that wrap another storage and
+// can replace keys only if the wrapped one can
+type replaceSupporter interface {
+	SupportsReplace() bool
+}
+
+// SupportsReplace reports whether Replace can be used with s.
+func SupportsReplace(s Storage) bool {
+	if r, ok := s.(replaceSupporter); ok {
+		return r.SupportsReplace()
+	}
+	_, ok := s.(Replacer)
+	return ok
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+)
+
+type memoryStorage struct {
+	data map[string][]byte
+}
+
+func (m *memoryStorage) Store(key string, val []byte) error {
+	if _, ok := m.data[key]; ok {
+		return ErrorKeyAlreadyExists
+	}
+	m.data[key] = val
+	return nil
+}
+
+func (m *memoryStorage) Load(key string) ([]byte, error) {
+	v, ok := m.data[key]
+	if !ok {
+		return nil, ErrorKeyNotFound
+	}
+	return v, nil
+}
+
+func (m *memoryStorage) Exists(key string) bool {
+	_, ok := m.data[key]
+	return ok
+}
+
+func (m *memoryStorage) Delete(key string) error {
+	delete(m.data, key)
+	return nil
+}
+
+func TestReplace_Unsupported(t *testing.T) {
+	m := &memoryStorage{data: map[string][]byte{"key": []byte("old")}}
+	require.False(t, SupportsReplace(m))
+	require.ErrorIs(t, Replace(m, "key", []byte("new")), ErrorReplaceNotSupported)
+	require.Equal(t, []byte("old"), m.data["key"])
+}
+
+func TestFileStorage_Replace(t *testing.T) {
+	s := &FileStorage{RootDir: t.TempDir()}
+	require.True(t, SupportsReplace(s))
+	require.ErrorIs(t, s.Replace("key", []byte("new")), ErrorKeyNotFound)
+
+	require.NoError(t, s.Store("key", []byte("old")))
+	require.NoError(t, Replace(s, "key", []byte("new")))
+
+	v, err := s.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), v)
+}
+
+func TestSymmetricEncryptStorage_Replace(t *testing.T) {
+	var key [KeyLength]byte
+	copy(key[:], "0123456789abcdef0123456789abcdef")
+
+	s := NewSymmetricEncryptStorage(key, &FileStorage{RootDir: t.TempDir()})
+	require.True(t, SupportsReplace(s))
+	require.NoError(t, s.Store("key", []byte("old")))
+	require.NoError(t, Replace(s, "key", []byte("new")))
+	v, err := s.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), v)
+
+	s = NewSymmetricEncryptStorage(key, &memoryStorage{data: map[string][]byte{}})
+	require.False(t, SupportsReplace(s))
+	require.NoError(t, s.Store("key", []byte("old")))
+	require.ErrorIs(t, s.Replace("key", []byte("new")), ErrorReplaceNotSupported)
+}
+
+func TestSymmetricEncryptStorage_StoredFormat(t *testing.T) {
+	var key [KeyLength]byte
+	copy(key[:], "0123456789abcdef0123456789abcdef")
+	m := &memoryStorage{data: map[string][]byte{}}
+	s := NewSymmetricEncryptStorage(key, m)
+
+	// the value is sealed with the key and nonce derived from the stored salt
+	require.NoError(t, s.Store("key", []byte("value")))
+	stored := m.data["key"]
+	keyNonce := make([]byte, KeyLength+symNonceLen)
+	_, err := io.ReadFull(hkdf.New(sha512.New, key[:], stored[:symSaltLen], encryptInfo), keyNonce)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(keyNonce[:KeyLength])
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	pt, err := gcm.Open(nil, keyNonce[KeyLength:], stored[symSaltLen:], nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), pt)
+
+	v, err := s.Load("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+
+	m.data["short"] = stored[:symSaltLen]
+	_, err = s.Load("short")
+	require.ErrorIs(t, err, ErrEncryptedDataInvalid)
+}